@@ -0,0 +1,88 @@
+package system
+
+// Returns the dependencies of every element of the system, as a hash map, where the key
+// of each record is the ID of an element. The hash map, and every slice in it, is a copy,
+// so modifying them would not affect the system.
+func (someSystem *System) AdjacencyMap () (map[string][]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	adjacency := make (map[string][]string, len (someSystem.dependencies))
+	for element, deps := range someSystem.dependencies {
+		depsCopy := make ([]string, len (deps))
+		copy (depsCopy, deps)
+		adjacency [element] = depsCopy
+	}
+	return adjacency
+}
+
+// Returns every dependency in the system, as an edge: a pair of the ID of an element,
+// followed by the ID of its dependency. Edges are given in the order their elements were
+// added to the system, and the edges of each element in the order of its dependencies,
+// so the same system always gives the same edges. Dependencies not in the system are
+// included.
+func (someSystem *System) Edges () ([][2]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	edges := [][2]string {}
+	for _, element := range someSystem.systemElements {
+		for _, dep := range someSystem.dependencies [element] {
+			edges = append (edges, [2]string {element, dep})
+		}
+	}
+	return edges
+}
+
+// Creates a new system from a hash map of the dependencies of every element, like the
+// one provided by AdjacencyMap (). Elements are added in the order of their IDs, each just
+// like with AddElement (), hence repeated dependencies are stored only once.
+//
+// Since a hash map has no order, the order in which elements were added to a system is
+// lost when the system goes through AdjacencyMap (). Hence, the "init order" of the new
+// system is the same as that of the original system, only if the elements of the original
+// system were added in the order of their IDs; otherwise, it is just as valid, but ties
+// may be broken differently.
+//
+// Inputs
+//
+// input 0: The dependencies of every element, where the key of each record is the ID of an
+// element.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error occurs, value would be nil.
+//
+// outpt 1: If any element could not be added, value would be the errors of all such
+// elements, just like in AddElements ().
+func FromAdjacencyMap (adjacency map[string][]string) (*System, error) {
+	newSystem := New ()
+	if errX := newSystem.AddElements (adjacency); errX != nil {
+		return nil, errX
+	}
+	return newSystem, nil
+}
+
+// Works out an "init order" for a hash map of the dependencies of every element, without
+// the need to create a system. It is the same as creating a system using
+// FromAdjacencyMap (), and calling its InitOrder ().
+//
+// Inputs
+//
+// input 0: The dependencies of every element, where the key of each record is the ID of an
+// element.
+//
+// Outpts
+//
+// outpt 0, outpt 1, and outpt 2: The same as those of InitOrder (). If the system could
+// not be created, value of outpt 1 would be the error of FromAdjacencyMap (), and value of
+// outpt 2 would be the text of that error.
+func TopoSort (edges map[string][]string) ([]string, error, string) {
+	someSystem, errX := FromAdjacencyMap (edges)
+	if errX != nil {
+		return nil, errX, errX.Error ()
+	}
+	return someSystem.InitOrder ()
+}
@@ -0,0 +1,995 @@
+package system
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gopkg.in/qamarian-etc/slices.v1"
+	"sort"
+)
+
+// Finds every cyclic dependency in the system. Unlike InitOrder (), which stops at the
+// first circle found, this operation reports all of them, using Tarjan's algorithm for
+// strongly connected components: each group of elements in which every element depends,
+// directly or indirectly, on every other element, is reported. An element that depends on
+// itself is also reported, as a group of its own. Dependencies not in the system are
+// ignored.
+//
+// Outpts
+//
+// outpt 0: The groups of elements forming circles. The IDs in each group are sorted, and
+// the groups are sorted by their first ID. If the system has no circle, value would be an
+// empty slice.
+func (someSystem *System) Cycles () ([][]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	return someSystem.cycles ()
+}
+
+func (someSystem *System) cycles () ([][]string) { /* This function is the lock-free
+	version of Cycles (), and is meant to be used by operations that already hold the
+	lock of the system. */
+
+	// Declaration of some data to be used for this operation. { ...
+	visitIndex := map[string]int {} // The order in which elements are visited.
+	lowLink := map[string]int {} /* The earliest visited element reachable from an
+		element, through elements not yet assigned to a group. */
+	groupStack := []string {} // Elements visited, but not yet assigned to a group.
+	onGroupStack := map[string]bool {}
+	cycles := [][]string {}
+	// ... }
+
+	type visit struct {
+		element string
+		nextDependency int
+	}
+
+	for _, root := range someSystem.systemElements {
+		if _, okX := visitIndex [root]; okX == true {
+			continue
+		}
+
+		/* The depth-first search of the algorithm is done using a stack held in
+			memory, rather than recursion, just like in InitOrder (). */
+		visitIndex [root], lowLink [root] = len (visitIndex), len (visitIndex)
+		groupStack = append (groupStack, root)
+		onGroupStack [root] = true
+		visitStack := []visit {{root, 0}}
+
+		for len (visitStack) > 0 {
+			top := &visitStack [len (visitStack) - 1]
+			deps := someSystem.orderingDependencies (top.element)
+
+			if top.nextDependency < len (deps) {
+				dependency := deps [top.nextDependency]
+				top.nextDependency ++
+				if someSystem.hasElement (dependency) == false {
+					continue
+				}
+				if _, okX := visitIndex [dependency]; okX == false {
+					visitIndex [dependency] = len (visitIndex)
+					lowLink [dependency] = visitIndex [dependency]
+					groupStack = append (groupStack, dependency)
+					onGroupStack [dependency] = true
+					visitStack = append (visitStack, visit {dependency, 0})
+				} else if onGroupStack [dependency] == true &&
+					visitIndex [dependency] < lowLink [top.element] {
+					lowLink [top.element] = visitIndex [dependency]
+				}
+				continue
+			}
+
+			// All dependencies of the element have been visited.
+			element := top.element
+			visitStack = visitStack [: len (visitStack) - 1]
+			if len (visitStack) > 0 {
+				parent := visitStack [len (visitStack) - 1].element
+				if lowLink [element] < lowLink [parent] {
+					lowLink [parent] = lowLink [element]
+				}
+			}
+			if lowLink [element] != visitIndex [element] {
+				continue
+			}
+
+			// The element is the first visited element of a group. { ...
+			group := []string {}
+			for {
+				member := groupStack [len (groupStack) - 1]
+				groupStack = groupStack [: len (groupStack) - 1]
+				onGroupStack [member] = false
+				group = append (group, member)
+				if member == element {
+					break
+				}
+			}
+			if len (group) > 1 || someSystem.isSelfDependent (element) == true {
+				sort.Strings (group)
+				cycles = append (cycles, group)
+			}
+			// ... }
+		}
+	}
+
+	sort.Slice (cycles, func (i, j int) (bool) {
+		return cycles [i][0] < cycles [j][0]
+	})
+	return cycles
+}
+
+func (someSystem *System) isSelfDependent (element string) (bool) { /* This function tells
+	if an element lists itself as a dependency. Such dependencies are rejected when
+	added, so this is only a safeguard. */
+	for _, dependency := range someSystem.dependencies [element] {
+		if dependency == element {
+			return true
+		}
+	}
+	return false
+}
+
+// Compares the system with another system, treating the system as the old version, and
+// the other system as the new version.
+//
+// Only the usual dependencies of elements are compared; optional dependencies are not.
+// Hence, systems that differ only in optional dependencies are reported to have no
+// difference, even though Equal () tells they are not equal, and their "init orders" may
+// differ.
+//
+// Inputs
+//
+// input 0: The new version of the system.
+//
+// Outpts
+//
+// outpt 0: The elements only in the other system. The IDs are sorted.
+//
+// outpt 1: The elements only in the system. The IDs are sorted.
+//
+// outpt 2: The elements in both systems, whose dependencies differ (the order of the
+// dependencies does not matter). The key of each record is the ID of an element, and the
+// value is its dependencies in the system, followed by its dependencies in the other
+// system.
+func (someSystem *System) Diff (other *System) (added, removed []string,
+	changed map[string][2][]string) {
+
+	snapshot := other.Clone () /* A copy is used, so the lock of the other system is not
+		needed while the lock of this system is held. */
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	added, removed, changed = []string {}, []string {}, map[string][2][]string {}
+	for _, element := range snapshot.systemElements {
+		if someSystem.hasElement (element) == false {
+			added = append (added, element)
+		}
+	}
+	for _, element := range someSystem.systemElements {
+		if snapshot.hasElement (element) == false {
+			removed = append (removed, element)
+			continue
+		}
+		oldDeps := someSystem.dependencies [element]
+		newDeps := snapshot.dependencies [element]
+		if sameDependencies (oldDeps, newDeps) == false {
+			oldDepsCopy := make ([]string, len (oldDeps))
+			copy (oldDepsCopy, oldDeps)
+			changed [element] = [2][]string {oldDepsCopy, newDeps}
+		}
+	}
+	sort.Strings (added)
+	sort.Strings (removed)
+	return added, removed, changed
+}
+
+// Tells if the system and another system describe the same graph: the same elements, each
+// with the same dependencies, and the same optional dependencies. The order in which
+// elements were added, and the order of the dependencies of each element, do not matter.
+func (someSystem *System) Equal (other *System) (bool) {
+
+	snapshot := other.Clone () /* A copy is used, so the lock of the other system is not
+		needed while the lock of this system is held. */
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if len (someSystem.systemElements) != len (snapshot.systemElements) {
+		return false
+	}
+	for _, element := range someSystem.systemElements {
+		if snapshot.hasElement (element) == false {
+			return false
+		}
+		if sameDependencies (someSystem.dependencies [element],
+			snapshot.dependencies [element]) == false {
+			return false
+		}
+		if sameDependencies (someSystem.optionalDependencies [element],
+			snapshot.optionalDependencies [element]) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// Works out a fingerprint of the system: a SHA-256 hash of its elements, their
+// dependencies, and their optional dependencies, given in hexadecimal. The order in which
+// elements were added, and the order of the dependencies of each element, do not affect
+// the fingerprint, hence systems that are equal (see Equal ()) always have the same
+// fingerprint, and systems that are not have different fingerprints, as far as can be
+// told. Tags, priorities, and attached data do not affect the fingerprint.
+func (someSystem *System) Fingerprint () (string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	/* Every ID is written with its length, so the boundaries of IDs are never lost,
+		whatever characters they contain. */
+	hash := sha256.New ()
+	writeIDs := func (mark string, ids []string) {
+		sorted := make ([]string, len (ids))
+		copy (sorted, ids)
+		sort.Strings (sorted)
+		fmt.Fprintf (hash, "%s%d;", mark, len (sorted))
+		for _, id := range sorted {
+			fmt.Fprintf (hash, "%d:%s", len (id), id)
+		}
+	}
+	writeIDs ("elements", someSystem.systemElements)
+	elements := make ([]string, len (someSystem.systemElements))
+	copy (elements, someSystem.systemElements)
+	sort.Strings (elements)
+	for _, element := range elements {
+		writeIDs ("dependencies", someSystem.dependencies [element])
+		writeIDs ("optional", someSystem.optionalDependencies [element])
+	}
+	return hex.EncodeToString (hash.Sum (nil))
+}
+
+// Finds the critical path of the system: the chain of elements, each depending on the one
+// before it, whose total initialization cost is the greatest. Elements of the critical
+// path can not be initialized concurrently, so its total cost is the least time needed to
+// initialize the system.
+//
+// Inputs
+//
+// input 0: The cost of initializing each element, where the key of each record is the ID
+// of an element. Elements without a record cost nothing.
+//
+// Outpts
+//
+// outpt 0: The elements of the critical path, starting with an element with no
+// dependency. When several paths have the greatest total cost, the one ending earliest in
+// the "init order" is given. If an error is encountered during the operation, value of
+// this data would be nil.
+//
+// outpt 1: The total cost of the critical path.
+//
+// outpt 2: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of InitOrder ().
+//
+// outpt 3: When the value of outpt 2 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) CriticalPath (cost map[string]int) ([]string, int, error,
+	string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	initOrder, errX, errDescp := someSystem.initOrder ()
+	if errX != nil {
+		return nil, 0, errX, errDescp
+	}
+
+	/* Since an element always comes after its dependencies in the "init order", the
+		costliest path ending at every dependency is known before the costliest
+		path ending at the element is worked out. */
+	pathCost := map[string]int {} // The cost of the costliest path ending at an element.
+	previous := map[string]string {} // The element before an element, on that path.
+	end := ""
+	for _, element := range initOrder {
+		best := 0
+		for index, dependency := range someSystem.orderingDependencies (element) {
+			if index == 0 || pathCost [dependency] > best {
+				best = pathCost [dependency]
+				previous [element] = dependency
+			}
+		}
+		pathCost [element] = best + cost [element]
+		if end == "" || pathCost [element] > pathCost [end] {
+			end = element
+		}
+	}
+
+	path := []string {}
+	if end == "" {
+		return path, 0, nil, ""
+	}
+	for element, okX := end, true; okX == true; element, okX = previous [element] {
+		path = append ([]string {element}, path...)
+	}
+	return path, pathCost [end], nil, ""
+}
+
+// Tells if making an element depend on another element would create a cyclic dependency.
+// The system is not modified.
+//
+// Inputs
+//
+// input 0: The ID of the element that would have the dependency.
+//
+// input 1: The ID of the would-be dependency.
+//
+// Outpts
+//
+// outpt 0: Value would be true, if input 0 and input 1 are the same element, or if input 1
+// already depends on input 0, directly or indirectly. If either element is not in the
+// system, value would be false.
+func (someSystem *System) WouldCycle (element, dependency string) (bool) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if someSystem.hasElement (element) == false ||
+		someSystem.hasElement (dependency) == false {
+		return false
+	}
+	return someSystem.dependencyPath (dependency, element) != nil
+}
+
+// Finds a chain of dependencies leading from one element to another, which explains why
+// the second element comes before the first in the "init order".
+//
+// Inputs
+//
+// input 0: The ID of the element the chain starts with.
+//
+// input 1: The ID of the element the chain ends with.
+//
+// Outpts
+//
+// outpt 0: The shortest chain of elements leading from input 0 to input 1, each element
+// depending on the one after it. If input 0 does not depend on input 1, directly or
+// indirectly, value would be an empty slice. If an error occurs, value would be nil.
+//
+// outpt 1: Possible errors include: ErrElementNotFound.
+func (someSystem *System) Path (from, to string) ([]string, error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if someSystem.hasElement (from) == false || someSystem.hasElement (to) == false {
+		return nil, ErrElementNotFound
+	}
+	path := someSystem.dependencyPath (from, to)
+	if path == nil {
+		return []string {}, nil
+	}
+	return path, nil
+}
+
+func (someSystem *System) dependencyPath (from, to string) ([]string) { /* This function
+	finds the shortest chain of dependencies leading from one element to another, using
+	a breadth-first search. The chain starts with "from" and ends with "to". If there is
+	no such chain, value nil is returned. Dependencies not in the system are ignored. It
+	is meant to be used by operations that already hold the lock of the system. */
+
+	previous := map[string]string {from: ""} /* The element through which each element
+		was reached. */
+	queue := []string {from}
+	for len (queue) > 0 {
+		element := queue [0]
+		queue = queue [1:]
+		if element == to {
+			path := []string {}
+			for ; element != ""; element = previous [element] {
+				path = append ([]string {element}, path...)
+			}
+			return path
+		}
+		for _, dep := range someSystem.orderingDependencies (element) {
+			if _, okX := previous [dep]; okX == true ||
+				someSystem.hasElement (dep) == false {
+				continue
+			}
+			previous [dep] = element
+			queue = append (queue, dep)
+		}
+	}
+	return nil
+}
+
+// Works out how many elements would be affected by a change to an element: the number of
+// distinct elements that depend on it, directly or indirectly. An element reachable
+// through several chains of dependents is counted once.
+//
+// Inputs
+//
+// input 0: The ID of the element.
+//
+// Outpts
+//
+// outpt 0: The number of elements that depend on the element, directly or indirectly. If
+// an error occurs, value would be 0.
+//
+// outpt 1: Possible errors include: ErrElementNotFound, and ErrCircleDetected (as
+// *CircleError), when a circle is found among the elements depending on the element.
+func (someSystem *System) BlastRadius (element string) (int, error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if someSystem.hasElement (element) == false {
+		return 0, ErrElementNotFound
+	}
+
+	/* The dependents are searched depth-first, using a stack held in memory. An element
+		met again while it is still on the stack means a circle. */
+	type visit struct {
+		element string
+		nextDependent int
+	}
+	visitStack := []visit {{element, 0}}
+	stackIndex := map[string]int {element: 0} // The position of elements on the stack.
+	visited := map[string]bool {element: true}
+
+	for len (visitStack) > 0 {
+		top := &visitStack [len (visitStack) - 1]
+		dependents := someSystem.dependents [top.element]
+		if top.nextDependent == len (dependents) {
+			delete (stackIndex, top.element)
+			visitStack = visitStack [: len (visitStack) - 1]
+			continue
+		}
+		dependent := dependents [top.nextDependent]
+		top.nextDependent ++
+
+		if index, okX := stackIndex [dependent]; okX == true {
+			/* Each element on the stack is depended on by the one after it, hence
+				the circle is the reverse of the stack. */
+			members := []string {}
+			for position := len (visitStack) - 1; position >= index; position -- {
+				members = append (members, visitStack [position].element)
+			}
+			return 0, &CircleError {canonicalCircle (members)}
+		}
+		if visited [dependent] == true {
+			continue
+		}
+		visited [dependent] = true
+		stackIndex [dependent] = len (visitStack)
+		visitStack = append (visitStack, visit {dependent, 0})
+	}
+	return len (visited) - 1, nil
+}
+
+// Finds the diamond dependencies of the system: elements reachable from some element
+// through more than one distinct chain of dependencies. For example, if "app" depends on
+// "api" and "worker", and both of them depend on "db", "db" is reachable from "app" through
+// two chains, and is reported. Such elements are often shared infrastructure, worth a
+// closer look. Dependencies not in the system are ignored.
+//
+// Outpts
+//
+// outpt 0: The elements found, in the order in which they were added to the system. If the
+// system has no diamond, value would be an empty slice. If the system has a cyclic
+// dependency, or an element depends on an element not in the system, value would be nil.
+func (someSystem *System) Diamonds () ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	initOrder, errX, _ := someSystem.initOrder ()
+	if errX != nil {
+		return nil
+	}
+
+	/* From each element, the chains of dependencies reaching every other element are
+		counted, with dependents visited before their dependencies. Counting stops at
+		two, as only knowing if there is more than one chain matters. */
+	found := map[string]bool {}
+	for _, source := range someSystem.systemElements {
+		chains := map[string]int {source: 1}
+		for index := len (initOrder) - 1; index >= 0; index -- {
+			element := initOrder [index]
+			if chains [element] == 0 {
+				continue
+			}
+			for _, dep := range someSystem.orderingDependencies (element) {
+				chains [dep] = min (chains [dep] + chains [element], 2)
+				if chains [dep] == 2 {
+					found [dep] = true
+				}
+			}
+		}
+	}
+
+	diamonds := []string {}
+	for _, element := range someSystem.systemElements {
+		if found [element] == true {
+			diamonds = append (diamonds, element)
+		}
+	}
+	return diamonds
+}
+
+// Suggests dependencies to remove, to rid the system of cyclic dependencies. Finding the
+// fewest such dependencies is a hard problem, so a heuristic is used: the dependencies
+// closing a circle, during a depth-first search of the elements in the order they were
+// added, are picked, and then every one of them that is not actually needed is dropped.
+// Hence, the suggestion may not be the smallest possible, but each dependency suggested
+// is needed: keeping any single one of them would leave a circle.
+//
+// Outpts
+//
+// outpt 0: The dependencies to remove, each given as the ID of an element, followed by
+// the ID of its dependency. If the system has no cyclic dependency, value would be an
+// empty slice. If an error occurs, value would be nil.
+//
+// outpt 1: Possible errors include: ErrElementMissing (as *MissingDependencyError), since
+// removing dependencies would not make an "init order" possible, while a dependency is
+// missing.
+func (someSystem *System) SuggestCycleBreaks () ([][2]string, error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	for _, element := range someSystem.systemElements {
+		for _, dep := range someSystem.orderingDependencies (element) {
+			if someSystem.hasElement (dep) == false {
+				return nil, &MissingDependencyError {element, dep,
+					someSystem.chainTo (element, dep)}
+			}
+		}
+	}
+
+	// Picking the dependencies closing a circle, during a depth-first search. { ...
+	type visit struct {
+		element string
+		nextDependency int
+	}
+	onStack := map[string]bool {}
+	visited := map[string]bool {}
+	breaks := [][2]string {}
+	removed := map[[2]string]bool {}
+	for _, root := range someSystem.systemElements {
+		if visited [root] == true {
+			continue
+		}
+		visited [root] = true
+		onStack [root] = true
+		visitStack := []visit {{root, 0}}
+		for len (visitStack) > 0 {
+			top := &visitStack [len (visitStack) - 1]
+			deps := someSystem.orderingDependencies (top.element)
+			if top.nextDependency == len (deps) {
+				delete (onStack, top.element)
+				visitStack = visitStack [: len (visitStack) - 1]
+				continue
+			}
+			dep := deps [top.nextDependency]
+			top.nextDependency ++
+
+			if onStack [dep] == true {
+				edge := [2]string {top.element, dep}
+				breaks = append (breaks, edge)
+				removed [edge] = true
+				continue
+			}
+			if visited [dep] == true {
+				continue
+			}
+			visited [dep] = true
+			onStack [dep] = true
+			visitStack = append (visitStack, visit {dep, 0})
+		}
+	}
+	// ... }
+
+	/* Each dependency picked is put back, unless its dependency could then reach the
+		element, through the dependencies not removed, since that would close a
+		circle again. */
+	reaches := func (from, to string) (bool) {
+		seen := map[string]bool {from: true}
+		queue := []string {from}
+		for len (queue) > 0 {
+			element := queue [0]
+			queue = queue [1:]
+			if element == to {
+				return true
+			}
+			for _, dep := range someSystem.orderingDependencies (element) {
+				if seen [dep] == true || removed [[2]string {element, dep}] == true {
+					continue
+				}
+				seen [dep] = true
+				queue = append (queue, dep)
+			}
+		}
+		return false
+	}
+	suggested := [][2]string {}
+	for _, edge := range breaks {
+		delete (removed, edge)
+		if reaches (edge [1], edge [0]) == true {
+			removed [edge] = true
+			suggested = append (suggested, edge)
+		}
+	}
+	return suggested, nil
+}
+
+// Finds the redundant dependencies of the system: the dependencies an element has
+// directly, while also having them indirectly, through another of its dependencies. For
+// example, if "a" depends on "b" and "c", and "b" depends on "c", the dependency of "a" on
+// "c" is redundant, as "a" would come after "c" in the "init order" even without it.
+// Only the usual dependencies are considered, as optional dependencies may be absent.
+//
+// Outpts
+//
+// outpt 0: The redundant dependencies, each given as the ID of an element, followed by the
+// ID of its dependency. They are given in the order their elements were added to the
+// system, and then in the order of the dependencies of each element. If an error is
+// encountered during the operation, value of this data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of InitOrder ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) RedundantEdges () ([][2]string, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	return someSystem.redundantEdges ()
+}
+
+func (someSystem *System) redundantEdges () ([][2]string, error, string) { /* This
+	function is the lock-free version of RedundantEdges (), and is meant to be used by
+	operations that already hold the lock of the system. */
+
+	initOrder, errX, errDescp := someSystem.initOrder ()
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+
+	/* Since an element always comes after its dependencies in the "init order", what
+		every dependency reaches is known before what the element reaches is worked
+		out. */
+	reaches := map[string]map[string]bool {} /* The direct and indirect dependencies of
+		each element. */
+	for _, element := range initOrder {
+		reached := map[string]bool {}
+		for _, dep := range someSystem.dependencies [element] {
+			reached [dep] = true
+			for indirectDep := range reaches [dep] {
+				reached [indirectDep] = true
+			}
+		}
+		reaches [element] = reached
+	}
+
+	redundant := [][2]string {}
+	for _, element := range someSystem.systemElements {
+		deps := someSystem.dependencies [element]
+		for _, dep := range deps {
+			for _, otherDep := range deps {
+				if otherDep != dep && reaches [otherDep][dep] == true {
+					redundant = append (redundant, [2]string {element, dep})
+					break
+				}
+			}
+		}
+	}
+	return redundant, nil, ""
+}
+
+// Creates the transitive reduction of the system: a copy of the system, without its
+// redundant dependencies (see RedundantEdges ()). Every element still depends, directly
+// or indirectly, on everything it did, so any "init order" of the new system is also a
+// valid "init order" of the system, while its dependencies are as few as possible.
+// Everything else known about the elements, like their tags, is copied as it is.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error is encountered during the operation, value of this
+// data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of InitOrder ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) TransitiveReduction () (*System, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	redundant, errX, errDescp := someSystem.redundantEdges ()
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+	isRedundant := map[[2]string]bool {}
+	for _, edge := range redundant {
+		isRedundant [edge] = true
+	}
+
+	newSystem := New ()
+	newSystem.options = someSystem.options
+	for _, element := range someSystem.systemElements {
+		newSystem.copyElement (someSystem, element)
+		deps := []string {}
+		for _, dep := range someSystem.dependencies [element] {
+			if isRedundant [[2]string {element, dep}] == false {
+				deps = append (deps, dep)
+			}
+		}
+		newSystem.setDependencies (element, deps)
+	}
+	return newSystem, nil, ""
+}
+
+// Works out the "init order" the system would have, if an element depended on another
+// element. The system itself is not modified; the dependency is added to a copy of it.
+// This could be used to preview the effect of a dependency, including whether it would
+// create a cyclic dependency, before adding it. The hook given using WithOrderHook (), if
+// any, is not called.
+//
+// Inputs
+//
+// input 0: The ID of the element that would have the dependency.
+//
+// input 1: The ID of the would-be dependency.
+//
+// Outpts
+//
+// outpt 0, outpt 1, and outpt 2: The same as those of InitOrder (), for the system with
+// the dependency added. Additionally, the errors of AddDependency () could be returned,
+// in which case value of outpt 2 would be the message of the error.
+func (someSystem *System) SimulateAddDependency (element, dependency string) ([]string,
+	error, string) {
+
+	simulation := someSystem.Clone ()
+	if errX := simulation.AddDependency (element, dependency); errX != nil {
+		return nil, errX, errX.Error ()
+	}
+
+	/* The copy is not shared, so it need not be locked, and using the lock-free
+		operation keeps the hook of the system from being called. */
+	return simulation.initOrder ()
+}
+
+// Splits the system into its connected components: groups of elements linked to one
+// another by dependencies, whichever way the dependencies go. Elements of different
+// components do not depend on one another, directly or indirectly, so the components
+// could be initialized independently, for example, concurrently. An element with no
+// dependency, that no element depends on, forms a component of its own. Optional
+// dependencies in the system link elements too, while dependencies not in the system are
+// ignored.
+//
+// Outpts
+//
+// outpt 0: The components. The IDs in each component are sorted, and the components are
+// sorted by their first ID. If the system has no element, value would be an empty slice.
+func (someSystem *System) Components () ([][]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	/* Components are found using a disjoint-set forest: every element starts in a
+		group of its own, and the groups of an element and each of its dependencies
+		are merged. */
+	parent := map[string]string {}
+	for _, element := range someSystem.systemElements {
+		parent [element] = element
+	}
+	find := func (element string) (string) {
+		for parent [element] != element {
+			parent [element] = parent [parent [element]]
+			element = parent [element]
+		}
+		return element
+	}
+	for _, element := range someSystem.systemElements {
+		for _, dep := range someSystem.orderingDependencies (element) {
+			if someSystem.hasElement (dep) == false {
+				continue
+			}
+			rootX, rootY := find (element), find (dep)
+			if rootX != rootY {
+				parent [rootY] = rootX
+			}
+		}
+	}
+
+	members := map[string][]string {} // The elements of each group, by its root.
+	for _, element := range someSystem.systemElements {
+		root := find (element)
+		members [root] = append (members [root], element)
+	}
+	components := make ([][]string, 0, len (members))
+	for _, component := range members {
+		sort.Strings (component)
+		components = append (components, component)
+	}
+	sort.Slice (components, func (i, j int) (bool) {
+		return components [i][0] < components [j][0]
+	})
+	return components
+}
+
+// Reports what removing an element would break, without removing it. Once an element is
+// removed (see RemoveElement ()), the elements that depend on it have a missing
+// dependency, and so do, indirectly, the elements depending on those, hence no "init
+// order" could be worked out until they are fixed. Optional dependencies are not
+// affected, as they need not be in the system.
+//
+// Inputs
+//
+// input 0: The ID of the element that would be removed.
+//
+// Outpts
+//
+// outpt 0: The elements that directly depend on the element, and would be left with a
+// missing dependency. The IDs are sorted. If an error occurs, value would be nil.
+//
+// outpt 1: All the elements that would be missing a dependency, directly or indirectly:
+// the elements of outpt 0, and every element depending on any of them, directly or
+// indirectly. The IDs are sorted. If an error occurs, value would be nil.
+//
+// outpt 2: Possible errors include: ErrElementNotFound.
+func (someSystem *System) RemovalImpact (element string) (dependents,
+	wouldBeMissing []string, err error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if someSystem.hasElement (element) == false {
+		return nil, nil, ErrElementNotFound
+	}
+
+	dependents = make ([]string, len (someSystem.dependents [element]))
+	copy (dependents, someSystem.dependents [element])
+	sort.Strings (dependents)
+
+	reached := map[string]bool {element: true}
+	queue := []string {element}
+	wouldBeMissing = []string {}
+	for len (queue) > 0 {
+		current := queue [0]
+		queue = queue [1:]
+		for _, dependent := range someSystem.dependents [current] {
+			if reached [dependent] == true {
+				continue
+			}
+			reached [dependent] = true
+			wouldBeMissing = append (wouldBeMissing, dependent)
+			queue = append (queue, dependent)
+		}
+	}
+	sort.Strings (wouldBeMissing)
+	return dependents, wouldBeMissing, nil
+}
+
+// Creates a higher-level view of the system, in which some elements are collapsed into a
+// single element: a new system, in which the elements are replaced by one new element.
+// The new element depends on everything the collapsed elements depend on, outside of
+// them, and every element depending on any of the collapsed elements depends on the new
+// element instead. Dependencies among the collapsed elements are discarded, so circles
+// formed only by collapsed elements disappear, while circles passing through other
+// elements become circles through the new element, to be reported by InitOrder (), as
+// usual. Optional dependencies are rewired the same way. The system itself is not
+// modified.
+//
+// The new element takes the place of the first collapsed element, in the order in which
+// elements were added. Tags, priorities, and attached data of the collapsed elements are
+// dropped, while those of other elements are kept.
+//
+// Inputs
+//
+// input 0: The IDs of the elements to be collapsed. At least one ID must be given.
+//
+// input 1: The ID of the new element. Value can not be an empty string, nor the ID of an
+// element of the system that is not collapsed.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error occurs, value would be nil.
+//
+// outpt 1: Possible errors include: ErrElementNotFound, naming the element not in the
+// system, and ErrAlreadyAdded (as *AlreadyAddedError). An error is also returned when no
+// element is given to be collapsed. Errors of the ID validator of the system, if any, are
+// also returned.
+func (someSystem *System) Collapse (ids []string, superID string) (*System, error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if len (ids) == 0 {
+		return nil, errors.New ("No element to be collapsed was given.")
+	}
+	collapsed := map[string]bool {}
+	for _, id := range ids {
+		if someSystem.hasElement (id) == false {
+			return nil, fmt.Errorf ("%w: element '%s'", ErrElementNotFound, id)
+		}
+		collapsed [id] = true
+	}
+	if superID == "" {
+		return nil, errors.New ("Empty string can not be used as ID of an element.")
+	}
+	if someSystem.options.idValidator != nil {
+		if errX := someSystem.options.idValidator (superID); errX != nil {
+			return nil, errX
+		}
+	}
+	if someSystem.hasElement (superID) == true && collapsed [superID] == false {
+		existing := make ([]string, len (someSystem.dependencies [superID]))
+		copy (existing, someSystem.dependencies [superID])
+		return nil, &AlreadyAddedError {superID, existing}
+	}
+
+	/* Dependencies are rewired by replacing the collapsed elements with the new
+		element, and dropping repetitions. The dependencies of the new element are
+		those of the collapsed elements, without the collapsed elements. */
+	rewire := func (deps []string, exclude []string) ([]string) {
+		rewired := []string {}
+		for _, dep := range deps {
+			if collapsed [dep] == true {
+				dep = superID
+			}
+			if slices.IsElementInStringSlice (rewired, dep) == false &&
+				slices.IsElementInStringSlice (exclude, dep) == false {
+				rewired = append (rewired, dep)
+			}
+		}
+		return rewired
+	}
+	superDeps := []string {}
+	superOptionalDeps := []string {}
+	for _, element := range someSystem.systemElements {
+		if collapsed [element] == true {
+			superDeps = append (superDeps, someSystem.dependencies [element]...)
+			superOptionalDeps = append (superOptionalDeps,
+				someSystem.optionalDependencies [element]...)
+		}
+	}
+	superDeps = rewire (superDeps, []string {superID})
+	superOptionalDeps = rewire (superOptionalDeps, append ([]string {superID},
+		superDeps...))
+
+	newSystem := New ()
+	newSystem.options = someSystem.options
+	for _, element := range someSystem.systemElements {
+		if collapsed [element] == true {
+			if newSystem.hasElement (superID) == true {
+				continue
+			}
+			element = superID
+			newSystem.systemElements = append (newSystem.systemElements, element)
+			newSystem.addedElements [element] = struct{} {}
+			newSystem.setDependencies (element, superDeps)
+			if len (superOptionalDeps) > 0 {
+				newSystem.optionalDependencies [element] = superOptionalDeps
+			}
+			continue
+		}
+
+		newSystem.copyElement (someSystem, element)
+		deps := rewire (someSystem.dependencies [element], nil)
+		newSystem.setDependencies (element, deps)
+		if _, okX := someSystem.optionalDependencies [element]; okX == true {
+			optionalDeps := rewire (someSystem.optionalDependencies [element], deps)
+			delete (newSystem.optionalDependencies, element)
+			if len (optionalDeps) > 0 {
+				newSystem.optionalDependencies [element] = optionalDeps
+			}
+		}
+	}
+	return newSystem, nil
+}
@@ -0,0 +1,47 @@
+package system
+
+// A builder allows a system to be built using chained calls, like the following:
+//
+// someSystem, errX := system.NewBuilder ().Add ("web", "db", "cache").Add ("db").Add (
+// 	"cache").Build ()
+//
+// Always use NewBuilder () to create new data of this type.
+type Builder struct {
+	system *System // The system being built.
+	err error // The first error encountered while building the system.
+}
+
+func NewBuilder () (*Builder) { // Creates a new builder.
+	return &Builder {New (), nil}
+}
+
+// Adds an element to the system being built, just like AddElement (). If an error has
+// already been encountered, nothing is done. Errors are reported by Build ().
+//
+// Inputs
+//
+// input 0: The new element to be added to the system.
+//
+// input 1: The IDs of the dependencies of the element.
+func (someBuilder *Builder) Add (element string, dependencies ...string) (*Builder) {
+	if someBuilder.err == nil {
+		someBuilder.err = someBuilder.system.AddElement (element, dependencies)
+	}
+	return someBuilder
+}
+
+// Returns the system built.
+//
+// Outpts
+//
+// outpt 0: The system built. If an error was encountered while building the system,
+// value would be nil.
+//
+// outpt 1: The first error encountered while building the system. Possible errors are the
+// same as those of AddElement ().
+func (someBuilder *Builder) Build () (*System, error) {
+	if someBuilder.err != nil {
+		return nil, someBuilder.err
+	}
+	return someBuilder.system, nil
+}
@@ -0,0 +1,84 @@
+package system
+
+import (
+	"context"
+)
+
+// The most IDs the cache of a system may hold, in total. When caching another result
+// would go beyond it, the cache is emptied first, so memory used by the cache stays
+// bounded.
+const cacheLimit = 1 << 16
+
+func (someSystem *System) orderFor (element string) ([]string, error, string) { /* This
+	function works out the "init order" of an element and its direct and indirect
+	dependencies, with the element itself last. The elements come in the same order as
+	in the "init order" of the whole system, and the depth limit of the system is
+	enforced among them. Results are cached, so asking again
+	for the same element, while the system is not modified, is cheap. The slice
+	returned is shared with the cache, and must not be modified. It is meant to be
+	used by operations that already hold the lock of the system. */
+
+	someSystem.cacheMutex.Lock ()
+	initOrder, okX := someSystem.cache [element]
+	someSystem.cacheMutex.Unlock ()
+	if okX == true {
+		return initOrder, nil, ""
+	}
+
+	initOrder, errX, errDescp := addToInitOrder (context.Background (), []string {},
+		map[string]bool {}, element, someSystem)
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+
+	/* Preferences, like priorities, are taken into account just like in the "init
+		order" of the whole system, so the elements come in the same order in both.
+		The elements are given in the order they were added to the system, just like
+		when the whole system is ordered. */
+	if someSystem.hasPreferences () == true {
+		included := make (map[string]bool, len (initOrder))
+		for _, orderedElement := range initOrder {
+			included [orderedElement] = true
+		}
+		elements := make ([]string, 0, len (initOrder))
+		for _, systemElement := range someSystem.systemElements {
+			if included [systemElement] == true {
+				elements = append (elements, systemElement)
+			}
+		}
+		initOrder, errX, errDescp = someSystem.orderByPreference (context.Background (),
+			elements)
+		if errX != nil {
+			return nil, errX, errDescp
+		}
+	}
+
+	depthOf := map[string]int {}
+	for _, orderedElement := range initOrder {
+		errY, errDescp := someSystem.checkDepth (orderedElement, depthOf)
+		if errY != nil {
+			return nil, errY, errDescp
+		}
+	}
+
+	someSystem.cacheMutex.Lock ()
+	defer someSystem.cacheMutex.Unlock ()
+	if someSystem.cache == nil || someSystem.cacheSize + len (initOrder) > cacheLimit {
+		someSystem.cache = map[string][]string {}
+		someSystem.cacheSize = 0
+	}
+	someSystem.cache [element] = initOrder
+	someSystem.cacheSize += len (initOrder)
+	return initOrder, nil, ""
+}
+
+func (someSystem *System) touch () { /* This function records that the system is being
+	modified: the modification is counted, and the cache of the system is emptied. It
+	must be called whenever the system is modified, and is meant to be used by
+	operations that already hold the lock of the system for writing. */
+	someSystem.modifications ++
+	someSystem.cacheMutex.Lock ()
+	defer someSystem.cacheMutex.Unlock ()
+	someSystem.cache = nil
+	someSystem.cacheSize = 0
+}
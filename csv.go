@@ -0,0 +1,106 @@
+package system
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// Creates a new system from CSV, as read by encoding/csv. Each row is a dependency of an
+// element: the ID of the element, followed by the ID of the dependency. An element with
+// no dependency has a row of its own, with only its ID. There is no header row. For
+// example:
+//
+// 	db
+// 	cache
+// 	web,db
+// 	web,cache
+//
+// Elements are added in the order they first appear in the first column, each just like
+// with AddElement (), with the dependencies of their rows, in the order of the rows.
+//
+// Inputs
+//
+// input 0: Where the CSV should be read from.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error occurs, value would be nil.
+//
+// outpt 1: Possible errors include: the errors of reading and decoding the CSV, and the
+// errors of AddElement ().
+func FromCSV (r io.Reader) (*System, error) {
+
+	reader := csv.NewReader (r)
+	reader.FieldsPerRecord = -1
+
+	elements := []string {}
+	dependencies := map[string][]string {}
+	for {
+		record, errX := reader.Read ()
+		if errX == io.EOF {
+			break
+		}
+		if errX != nil {
+			return nil, errX
+		}
+		line, _ := reader.FieldPos (0)
+		if len (record) > 2 {
+			return nil, fmt.Errorf ("line %d: a row has %d fields, but at most 2 " +
+				"are expected", line, len (record))
+		}
+
+		element := record [0]
+		if _, okX := dependencies [element]; okX == false {
+			elements = append (elements, element)
+			dependencies [element] = []string {}
+		}
+		if len (record) == 2 && record [1] != "" {
+			dependencies [element] = append (dependencies [element], record [1])
+		}
+	}
+
+	newSystem := New ()
+	for _, element := range elements {
+		if errY := newSystem.AddElement (element, dependencies [element]); errY != nil {
+			return nil, errY
+		}
+	}
+	return newSystem, nil
+}
+
+// Writes the system as CSV, in the form read by FromCSV (). Elements appear in the order
+// they were added to the system, so the "init order" survives a round trip, provided no
+// element has optional dependencies or a priority: the form has no place for them, hence
+// they are not written.
+//
+// Inputs
+//
+// input 0: Where the CSV should be written.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: the errors of encoding and writing the CSV.
+func (someSystem *System) ToCSV (w io.Writer) (error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	writer := csv.NewWriter (w)
+	for _, element := range someSystem.systemElements {
+		deps := someSystem.dependencies [element]
+		if len (deps) == 0 {
+			if errX := writer.Write ([]string {element}); errX != nil {
+				return errX
+			}
+			continue
+		}
+		for _, dep := range deps {
+			if errY := writer.Write ([]string {element, dep}); errY != nil {
+				return errY
+			}
+		}
+	}
+	writer.Flush ()
+	return writer.Error ()
+}
@@ -0,0 +1,61 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writes the system as a Graphviz digraph. Every element is a node, and every dependency
+// of an element is an edge pointing from the element to the dependency. The output could
+// be fed directly to Graphviz, for example: dot -Tpng. The digraph is written line by
+// line, as it is produced, so the memory used does not grow with the size of the system.
+//
+// Inputs
+//
+// input 0: Where the digraph should be written.
+//
+// Outpts
+//
+// outpt 0: If writing fails, value would be the error returned by the writer.
+func (someSystem *System) WriteDOT (w io.Writer) (error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	buffer := bufio.NewWriter (w)
+	_, errX := fmt.Fprintln (buffer, "digraph system {")
+	if errX != nil {
+		return errX
+	}
+
+	for _, element := range someSystem.systemElements {
+		_, errY := fmt.Fprintf (buffer, "\t%s;\n", quoteDOTID (element))
+		if errY != nil {
+			return errY
+		}
+	}
+	for _, element := range someSystem.systemElements {
+		for _, dependency := range someSystem.dependencies [element] {
+			_, errZ := fmt.Fprintf (buffer, "\t%s -> %s;\n", quoteDOTID (element),
+				quoteDOTID (dependency))
+			if errZ != nil {
+				return errZ
+			}
+		}
+	}
+
+	if _, errX = fmt.Fprintln (buffer, "}"); errX != nil {
+		return errX
+	}
+	return buffer.Flush ()
+}
+
+var dotEscaper = strings.NewReplacer (`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+func quoteDOTID (id string) (string) { /* This function turns an ID into a quoted DOT ID,
+	so IDs containing quotes, spaces, or any other special character could be used
+	as they are. */
+	return `"` + dotEscaper.Replace (id) + `"`
+}
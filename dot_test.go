@@ -0,0 +1,9 @@
+package system
+
+import (
+	"testing"
+)
+
+func BenchmarkWriteDOT (b *testing.B) {
+	benchmarkWrite (b, (*System).WriteDOT)
+}
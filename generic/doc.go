@@ -0,0 +1,4 @@
+// This package implements the ADT 'system', for elements identified by any comparable
+// type, instead of strings alone. Always use New () or NewZeroAllowed () to create new
+// data of this type.
+package generic
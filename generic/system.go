@@ -0,0 +1,158 @@
+package generic
+
+import (
+	"errors"
+	"fmt"
+)
+
+func New[T comparable] () (*System[T]) { /* Creates a new system. The zero value of T can
+	not be used as the ID of an element, just like the empty string can not be used
+	in the string-only version of the system. */
+	return &System[T] {[]T {}, map[T][]T {}, false}
+}
+
+func NewZeroAllowed[T comparable] () (*System[T]) { /* Creates a new system, in which the
+	zero value of T can be used as the ID of an element. */
+	return &System[T] {[]T {}, map[T][]T {}, true}
+}
+
+type System[T comparable] struct {
+	systemElements []T // All elements in the system.
+	dependencies map[T][]T /* The dependencies of individual elements in the system. The
+		list of dependencies of each individual element, would be stored in this
+		hash map, where the key of each record would be the ID of the element. */
+	zeroAllowed bool // Tells if the zero value of T can be used as an ID.
+}
+
+// Adds an element to the system.
+//
+// Inputs
+//
+// input 0: The new element to be added to the system. Value can not be the zero value of
+// T, unless the system was created using NewZeroAllowed ().
+//
+// input 1: The IDs of the dependencies of the element. Just like input 0, the ID of a
+// dependency may not be the zero value of T, unless the system was created using
+// NewZeroAllowed ().
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrAlreadyAdded, ErrZeroValue.
+func (someSystem *System[T]) AddElement (newElement T, dependencies []T) (error) {
+
+	var zero T
+	if someSystem.zeroAllowed == false {
+		if newElement == zero {
+			return ErrZeroValue
+		}
+		for _, dep := range dependencies {
+			if dep == zero {
+				return ErrZeroValue
+			}
+		}
+	}
+	if _, okX := someSystem.dependencies [newElement]; okX == true {
+		return ErrAlreadyAdded
+	}
+	depsCopy := make ([]T, len (dependencies))
+	copy (depsCopy, dependencies)
+	someSystem.systemElements = append (someSystem.systemElements, newElement)
+	someSystem.dependencies [newElement] = depsCopy
+	return nil
+}
+
+// Tells if an element is in the system.
+func (someSystem *System[T]) HasElement (element T) (bool) {
+	_, okX := someSystem.dependencies [element]
+	return okX
+}
+
+// This functions provides an order in which elements of the system could be safely
+// initialized. Just like in the string-only version of the system, ties are broken using
+// the order in which elements were added, and the order in which the dependencies of each
+// element were given.
+//
+// Outpts
+// outpt 0: A slice of the IDs of the elements in the system. The ascending order of these
+// IDs represents the "init order". If an error is encountered during the operation, value
+// of this data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors include: ErrElementMissing, ErrCircleDetected.
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error. Possible values would look like the following:
+//
+// "Dependency '7' is missing" - Value of outpt 2 when a dependency of an element is not
+// in the system.
+//
+// "Element '3' is part of the circle '3 -> 4 -> 3'." - Value of outpt 2 when a cyclic
+// dependency is detected.
+func (someSystem *System[T]) InitOrder () ([]T, error, string) {
+
+	initOrder := []T {}
+	ordered := map[T]bool {}
+	for _, element := range someSystem.systemElements {
+		if ordered [element] == true {
+			continue
+		}
+		var errX error
+		var errDescp string
+		initOrder, errX, errDescp = someSystem.addToInitOrder (initOrder, ordered,
+			element, []T {})
+		if errX != nil {
+			return nil, errX, errDescp
+		}
+	}
+	return initOrder, nil, ""
+}
+
+func (someSystem *System[T]) addToInitOrder (initOrder []T, ordered map[T]bool, element T,
+	waitingList []T) ([]T, error, string) { /* This function is not meant to be used
+	outside this package. The function simply takes an init order and an element, then
+	adds the element, and all its dependencies not yet added, to a safe place in the
+	"init order". Data "ordered" keeps track of the elements already in the "init
+	order", while data "waitingList" is the stack of the elements waiting for their
+	dependencies to be added first. */
+
+	// Checking for existence of a circle.
+	for index, waiting := range waitingList {
+		if waiting == element {
+			circle := ""
+			for _, member := range waitingList [index:] {
+				circle += fmt.Sprintf ("%v -> ", member)
+			}
+			return nil, ErrCircleDetected, fmt.Sprintf (
+				"Element '%v' is part of the circle '%s%v'.", element, circle,
+				element)
+		}
+	}
+
+	waitingList = append (waitingList, element)
+	for _, dependency := range someSystem.dependencies [element] {
+		if ordered [dependency] == true {
+			continue
+		}
+		if someSystem.HasElement (dependency) == false {
+			return nil, ErrElementMissing, fmt.Sprintf (
+				"Dependency '%v' is missing", dependency)
+		}
+		var errX error
+		var errDescp string
+		initOrder, errX, errDescp = someSystem.addToInitOrder (initOrder, ordered,
+			dependency, waitingList)
+		if errX != nil {
+			return nil, errX, errDescp
+		}
+	}
+
+	ordered [element] = true
+	return append (initOrder, element), nil, ""
+}
+
+var (
+	ErrAlreadyAdded error = errors.New ("The element has already been added")
+	ErrCircleDetected error = errors.New ("A circle has been detected")
+	ErrElementMissing error = errors.New ("An element is missing")
+	ErrZeroValue error = errors.New ("The zero value can not be used as an ID")
+)
@@ -0,0 +1,78 @@
+package system
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+type gobElement struct { /* The form in which an element is represented, when a system
+	is encoded using gob. Elements are kept in a list, so the order in which they were
+	added to the system is preserved. */
+	ID string
+	Dependencies []string
+	OptionalDependencies []string
+	Tag string
+	Priority int
+}
+
+// Encodes the system for encoding/gob. Elements, their dependencies, their optional
+// dependencies, their tags, and their priorities are encoded, in the order the elements
+// were added to the system, so a decoded system has the same "init order" as the
+// original. The data attached to elements using AddElementMeta () is not encoded, as it
+// could be of any type.
+func (someSystem *System) GobEncode () ([]byte, error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	encoding := make ([]gobElement, 0, len (someSystem.systemElements))
+	for _, element := range someSystem.systemElements {
+		encoding = append (encoding, gobElement {element,
+			someSystem.dependencies [element],
+			someSystem.optionalDependencies [element], someSystem.tags [element],
+			someSystem.priorities [element]})
+	}
+
+	var data bytes.Buffer
+	if errX := gob.NewEncoder (&data).Encode (encoding); errX != nil {
+		return nil, errX
+	}
+	return data.Bytes (), nil
+}
+
+// Decodes a system encoded by GobEncode (). Whatever the system contained before is
+// discarded, but its options are kept. The elements are added one after the other, just
+// like with AddElementOpt (), hence the errors of AddElementOpt () could be returned.
+func (someSystem *System) GobDecode (data []byte) (error) {
+
+	encoding := []gobElement {}
+	errX := gob.NewDecoder (bytes.NewReader (data)).Decode (&encoding)
+	if errX != nil {
+		return errX
+	}
+
+	/* The elements are added to a system with the options of this system, so its ID
+		validator, and its strictness about dependencies, apply to them. */
+	newSystem := New ()
+	newSystem.options = someSystem.options
+	for _, element := range encoding {
+		errY := newSystem.AddElementOpt (element.ID, element.Dependencies,
+			element.OptionalDependencies)
+		if errY != nil {
+			return errY
+		}
+		if element.Tag != "" {
+			newSystem.tags [element.ID] = element.Tag
+		}
+		if element.Priority != 0 {
+			newSystem.priorities [element.ID] = element.Priority
+		}
+	}
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+	someSystem.adopt (newSystem)
+	return nil
+}
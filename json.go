@@ -0,0 +1,114 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+type jsonSystem struct { /* The form in which a system is represented in JSON. Elements
+	are kept in a list, instead of an object, so the order in which they were added
+	to the system is preserved. */
+	Elements []jsonElement `json:"elements"`
+}
+
+type jsonElement struct {
+	ID string `json:"id"`
+	Dependencies []string `json:"dependencies"`
+	OptionalDependencies []string `json:"optional_dependencies,omitempty"`
+	Priority int `json:"priority,omitempty"`
+}
+
+// Encodes the system as JSON. The encoding looks like the following:
+//
+// {"elements":[{"id":"a","dependencies":[]},{"id":"b","dependencies":["a"]}]}
+//
+// The optional dependencies of an element, if any, are encoded in field
+// "optional_dependencies" of the element, and its priority, if not 0, in field
+// "priority". Elements are encoded in the order they were
+// added to the system, so the encoding of a system is always the same.
+func (someSystem *System) MarshalJSON () ([]byte, error) {
+	var encoding bytes.Buffer
+	if errX := someSystem.WriteJSON (&encoding); errX != nil {
+		return nil, errX
+	}
+	return encoding.Bytes (), nil
+}
+
+// Writes the system as JSON, in the same encoding as MarshalJSON (). Unlike
+// MarshalJSON (), the encoding is written element by element, as it is produced, so the
+// memory used does not grow with the size of the system.
+//
+// Inputs
+//
+// input 0: Where the JSON should be written.
+//
+// Outpts
+//
+// outpt 0: If writing fails, value would be the error returned by the writer.
+func (someSystem *System) WriteJSON (w io.Writer) (error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	buffer := bufio.NewWriter (w)
+	if _, errX := buffer.WriteString (`{"elements":[`); errX != nil {
+		return errX
+	}
+	for index, element := range someSystem.systemElements {
+		encoding, errX := json.Marshal (jsonElement {element,
+			someSystem.dependencies [element],
+			someSystem.optionalDependencies [element],
+			someSystem.priorities [element]})
+		if errX != nil {
+			return errX
+		}
+		if index > 0 {
+			if errY := buffer.WriteByte (','); errY != nil {
+				return errY
+			}
+		}
+		if _, errY := buffer.Write (encoding); errY != nil {
+			return errY
+		}
+	}
+	if _, errX := buffer.WriteString ("]}"); errX != nil {
+		return errX
+	}
+	return buffer.Flush ()
+}
+
+// Decodes a system encoded by MarshalJSON (). Whatever the system contained before is
+// discarded. The elements are added one after the other, just like with
+// AddElementOpt (), hence the errors of AddElementOpt () could be returned.
+func (someSystem *System) UnmarshalJSON (data []byte) (error) {
+
+	encoding := jsonSystem {}
+	errX := json.Unmarshal (data, &encoding)
+	if errX != nil {
+		return errX
+	}
+
+	/* The elements are added to a system with the options of this system, so its ID
+		validator, and its strictness about dependencies, apply to them. */
+	newSystem := New ()
+	newSystem.options = someSystem.options
+	for _, element := range encoding.Elements {
+		errY := newSystem.AddElementOpt (element.ID, element.Dependencies,
+			element.OptionalDependencies)
+		if errY != nil {
+			return errY
+		}
+		if element.Priority != 0 {
+			newSystem.priorities [element.ID] = element.Priority
+		}
+	}
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+	someSystem.adopt (newSystem)
+	return nil
+}
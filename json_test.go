@@ -0,0 +1,54 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func BenchmarkWriteJSON (b *testing.B) {
+	benchmarkWrite (b, (*System).WriteJSON)
+}
+
+func TestJSONOptionalDependencies (t *testing.T) {
+
+	someSystem := New ()
+	someSystem.AddElementOpt ("a", []string {}, []string {"b"})
+	someSystem.AddElement ("b", []string {})
+
+	encoding, errX := json.Marshal (someSystem)
+	if errX != nil {
+		t.Fatalf ("The system could not be encoded: %v", errX)
+	}
+	decoded := New ()
+	if errY := json.Unmarshal (encoding, decoded); errY != nil {
+		t.Fatalf ("The system could not be decoded: %v", errY)
+	}
+	if decoded.Equal (someSystem) == false {
+		t.Fatalf ("The system decoded from %s differs from the one encoded.", encoding)
+	}
+	initOrder, _, _ := decoded.InitOrder ()
+	if fmt.Sprint (initOrder) != "[b a]" {
+		t.Fatalf ("The \"init order\" is %v, rather than [b a].", initOrder)
+	}
+}
+
+func TestJSONPriorities (t *testing.T) {
+
+	someSystem := New ()
+	someSystem.AddElement ("a", []string {})
+	someSystem.AddElementPrio ("b", []string {}, 5)
+
+	encoding, errX := json.Marshal (someSystem)
+	if errX != nil {
+		t.Fatalf ("The system could not be encoded: %v", errX)
+	}
+	decoded := New ()
+	if errY := json.Unmarshal (encoding, decoded); errY != nil {
+		t.Fatalf ("The system could not be decoded: %v", errY)
+	}
+	initOrder, _, _ := decoded.InitOrder ()
+	if fmt.Sprint (initOrder) != "[b a]" {
+		t.Fatalf ("The \"init order\" is %v, rather than [b a].", initOrder)
+	}
+}
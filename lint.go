@@ -0,0 +1,131 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A problem found in a system by Lint ().
+type Problem struct {
+	Kind ProblemKind
+	Elements []string // The elements involved in the problem.
+	Description string // A description of the problem, for people to read.
+}
+
+// The kind of a problem found by Lint ().
+type ProblemKind int
+
+const (
+	// An element depends on an element not in the system. The elements involved are
+	// the element, and its missing dependency.
+	ProblemMissingDependency ProblemKind = iota
+	// Some elements depend on one another in a circle. The elements involved are the
+	// members of the circle, sorted.
+	ProblemCycle
+	// An element lists itself as a dependency. The element is the only one involved.
+	// Such dependencies are rejected when given, so this is only a safeguard.
+	ProblemSelfDependency
+	// An element lists a dependency more than once. The elements involved are the
+	// element, and the dependency. Dependencies are stored only once, so this is only a
+	// safeguard.
+	ProblemDuplicateDependency
+	// An element has no dependency, and no element depends on it, in a system with
+	// other elements. The element is the only one involved.
+	ProblemOrphan
+)
+
+// Returns the name of a kind of problem, like "missing dependency".
+func (kind ProblemKind) String () (string) {
+	switch kind {
+	case ProblemMissingDependency:
+		return "missing dependency"
+	case ProblemCycle:
+		return "cycle"
+	case ProblemSelfDependency:
+		return "self-dependency"
+	case ProblemDuplicateDependency:
+		return "duplicate dependency"
+	case ProblemOrphan:
+		return "orphan"
+	}
+	return fmt.Sprintf ("ProblemKind(%d)", int (kind))
+}
+
+// Checks the system for every problem it has, at once. Unlike Validate (), which stops at
+// the first problem found, this operation reports all of them, so they could all be fixed
+// at once. Orphans do not stop an "init order" from being worked out, but are reported as
+// they are often a mistake.
+//
+// Outpts
+//
+// outpt 0: The problems found. Problems are grouped by kind, in the order the kinds are
+// declared; missing dependencies, duplicate dependencies, and orphans are given in the
+// order in which their elements were added to the system, and cycles just like in
+// Cycles (). If the system has no problem, value would be an empty slice.
+func (someSystem *System) Lint () ([]Problem) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	problems := []Problem {}
+	for _, element := range someSystem.systemElements {
+		for _, dep := range someSystem.orderingDependencies (element) {
+			if someSystem.hasElement (dep) == false {
+				problems = append (problems, Problem {ProblemMissingDependency,
+					[]string {element, dep}, fmt.Sprintf ("Dependency '%s' " +
+					"of element '%s' is missing", dep, element)})
+			}
+		}
+	}
+
+	selfDependent := []Problem {}
+	for _, cycle := range someSystem.cycles () {
+		if len (cycle) == 1 && someSystem.isSelfDependent (cycle [0]) == true {
+			selfDependent = append (selfDependent, Problem {ProblemSelfDependency,
+				cycle, fmt.Sprintf ("Element '%s' depends on itself", cycle [0])})
+			continue
+		}
+		problems = append (problems, Problem {ProblemCycle, cycle, fmt.Sprintf (
+			"Elements %s depend on one another in a circle",
+			"'" + strings.Join (cycle, "', '") + "'")})
+	}
+	problems = append (problems, selfDependent...)
+
+	for _, element := range someSystem.systemElements {
+		seen := map[string]bool {}
+		for _, dep := range someSystem.dependencies [element] {
+			if seen [dep] == true {
+				problems = append (problems, Problem {ProblemDuplicateDependency,
+					[]string {element, dep}, fmt.Sprintf ("Element '%s' lists " +
+					"dependency '%s' more than once", element, dep)})
+			}
+			seen [dep] = true
+		}
+	}
+
+	if len (someSystem.systemElements) > 1 {
+		for _, element := range someSystem.systemElements {
+			if len (someSystem.orderingDependencies (element)) == 0 &&
+				len (someSystem.dependents [element]) == 0 &&
+				someSystem.isOptionallyDependedOn (element) == false {
+				problems = append (problems, Problem {ProblemOrphan,
+					[]string {element}, fmt.Sprintf ("Element '%s' has no " +
+					"dependency, and no element depends on it", element)})
+			}
+		}
+	}
+	return problems
+}
+
+func (someSystem *System) isOptionallyDependedOn (element string) (bool) { /* This
+	function tells if some element lists an element as an optional dependency. It is
+	meant to be used by operations that already hold the lock of the system. */
+	for _, optionalDeps := range someSystem.optionalDependencies {
+		for _, dep := range optionalDeps {
+			if dep == element {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package system
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writes the system as a Mermaid flowchart, which could be embedded in Markdown. Every
+// element is a node, and every dependency of an element is an edge pointing from the
+// element to the dependency.
+//
+// Since Mermaid restricts the characters of node IDs, nodes are given IDs of their own
+// (n0, n1, ...), and the ID of each element is used as the label of its node. Labels are
+// quoted, and quotes within them escaped, so IDs containing spaces, or any other special
+// character, could be used as they are.
+//
+// Inputs
+//
+// input 0: Where the flowchart should be written.
+//
+// Outpts
+//
+// outpt 0: If writing fails, value would be the error returned by the writer.
+func (someSystem *System) WriteMermaid (w io.Writer) (error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if _, errX := fmt.Fprintln (w, "graph TD"); errX != nil {
+		return errX
+	}
+
+	nodeIDs := map[string]string {}
+	writeNode := func (id string) (string, error) { /* Writes the node of an ID, if not
+		yet written, and returns the node ID given to it. */
+		if nodeID, okX := nodeIDs [id]; okX == true {
+			return nodeID, nil
+		}
+		nodeID := fmt.Sprintf ("n%d", len (nodeIDs))
+		nodeIDs [id] = nodeID
+		_, errY := fmt.Fprintf (w, "\t%s[\"%s\"]\n", nodeID, mermaidEscaper.Replace (id))
+		return nodeID, errY
+	}
+
+	for _, element := range someSystem.systemElements {
+		if _, errX := writeNode (element); errX != nil {
+			return errX
+		}
+	}
+	for _, element := range someSystem.systemElements {
+		for _, dependency := range someSystem.dependencies [element] {
+			dependencyNode, errX := writeNode (dependency)
+			if errX != nil {
+				return errX
+			}
+			_, errY := fmt.Fprintf (w, "\t%s --> %s\n", nodeIDs [element],
+				dependencyNode)
+			if errY != nil {
+				return errY
+			}
+		}
+	}
+	return nil
+}
+
+var mermaidEscaper = strings.NewReplacer (`"`, "#quot;", "\n", " ", "\r", " ")
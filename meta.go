@@ -0,0 +1,53 @@
+package system
+
+// Adds an element to the system, just like AddElement (), and attaches some data to it.
+// The data could be anything, for example, a description, a version, or the function
+// that initializes the element. It is dropped when the element is removed.
+//
+// Inputs
+//
+// input 0: The new element to be added to the system.
+//
+// input 1: The IDs of the dependencies of the element.
+//
+// input 2: The data to be attached to the element. The system keeps the value as it is;
+// copies of the system share it.
+//
+// Outpts
+//
+// outpt 0: Possible errors are the same as those of AddElement ().
+func (someSystem *System) AddElementMeta (newElement string, dependencies []string,
+	meta any) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if errX := someSystem.addElement (newElement, dependencies); errX != nil {
+		return errX
+	}
+	someSystem.metadata [newElement] = meta
+	return nil
+}
+
+// Returns the data attached to an element, using AddElementMeta ().
+//
+// Inputs
+//
+// input 0: The ID of the element.
+//
+// Outpts
+//
+// outpt 0: The data attached to the element.
+//
+// outpt 1: Value would be false, if the element is not in the system, or no data has been
+// attached to it.
+func (someSystem *System) Meta (element string) (any, bool) {
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+	meta, okX := someSystem.metadata [element]
+	return meta, okX
+}
@@ -0,0 +1,82 @@
+package system
+
+import (
+	"gopkg.in/qamarian-etc/slices.v1"
+)
+
+// Adds an element to the system, just like AddElement (), with optional dependencies in
+// addition to the usual ones. An optional dependency affects the "init order" only when
+// it is in the system: the element then comes after it, just like after any other
+// dependency. When it is not in the system, it is simply ignored, and no error is
+// reported for it. This suits, for example, plugins that may or may not be installed.
+//
+// Optional dependencies are accounted for by every operation concerned with the order of
+// elements. Operations concerned with the declared dependencies of elements, like
+// Dependencies () and Dependents (), only report the usual dependencies.
+//
+// Inputs
+//
+// input 0: The new element to be added to the system.
+//
+// input 1: The IDs of the dependencies of the element, which must be in the system.
+//
+// input 2: The IDs of the optional dependencies of the element. Just like the usual
+// dependencies, the ID of an optional dependency may not be an empty string, nor the ID
+// of the element itself. Optional dependencies that are also usual dependencies are
+// ignored.
+//
+// Outpts
+//
+// outpt 0: Possible errors are the same as those of AddElement ().
+func (someSystem *System) AddElementOpt (newElement string, dependencies,
+	optionalDependencies []string) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	optionalDeps, errX := someSystem.prepareDependencies (newElement,
+		optionalDependencies, true)
+	if errX != nil {
+		return errX
+	}
+	if errY := someSystem.addElement (newElement, dependencies); errY != nil {
+		return errY
+	}
+
+	onlyOptionalDeps := []string {}
+	for _, dep := range optionalDeps {
+		if slices.IsElementInStringSlice (someSystem.dependencies [newElement],
+			dep) == false {
+			onlyOptionalDeps = append (onlyOptionalDeps, dep)
+		}
+	}
+	if len (onlyOptionalDeps) > 0 {
+		someSystem.optionalDependencies [newElement] = onlyOptionalDeps
+	}
+	return nil
+}
+
+func (someSystem *System) orderingDependencies (element string) ([]string) { /* This
+	function returns the dependencies that decide where an element could be placed in
+	the "init order": its usual dependencies, followed by its optional dependencies
+	that are in the system. It is meant to be used by operations that already hold the
+	lock of the system. */
+
+	optionalDeps := someSystem.optionalDependencies [element]
+	if len (optionalDeps) == 0 {
+		return someSystem.dependencies [element]
+	}
+	deps := make ([]string, len (someSystem.dependencies [element]),
+		len (someSystem.dependencies [element]) + len (optionalDeps))
+	copy (deps, someSystem.dependencies [element])
+	for _, dep := range optionalDeps {
+		if someSystem.hasElement (dep) == true {
+			deps = append (deps, dep)
+		}
+	}
+	return deps
+}
@@ -0,0 +1,47 @@
+package system
+
+// Adds an element to the system, just like AddElement (), and gives it a priority.
+// Whenever more than one element could come next in the "init order", the one with the
+// highest priority comes first; dependencies are still always respected. Elements added
+// using AddElement () have priority 0.
+//
+// Once any element of the system has a priority, InitOrder (), and every operation based
+// on it, builds the "init order" by repeatedly picking, among the elements whose
+// dependencies are all already placed, the one with the highest priority. Ties between
+// elements with the same priority are broken using the order of their IDs (or the
+// comparator of the system, see WithReadyComparator ()), rather than the order in which
+// elements were added to the system. Hence, the "init order" could differ from the one
+// the system would have without priorities, even among elements with the same
+// priority. Elements are also no longer yielded by InitOrderSeq () as soon as their
+// place is known, as the whole "init order" must be worked out first.
+//
+// Inputs
+//
+// input 0: The new element to be added to the system.
+//
+// input 1: The IDs of the dependencies of the element.
+//
+// input 2: The priority of the element. Value could be negative, to make an element come
+// after others.
+//
+// Outpts
+//
+// outpt 0: Possible errors are the same as those of AddElement ().
+func (someSystem *System) AddElementPrio (newElement string, dependencies []string,
+	prio int) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if errX := someSystem.addElement (newElement, dependencies); errX != nil {
+		return errX
+	}
+	if prio != 0 {
+		someSystem.priorities [newElement] = prio
+	}
+	return nil
+}
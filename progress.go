@@ -0,0 +1,70 @@
+package system
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// The outcome of an operation run in the background, like InitOrderWithProgress ().
+type Result struct {
+	InitOrder []string // Just like outpt 0 of InitOrder ().
+	Err error // Just like outpt 1 of InitOrder ().
+	Description string // Just like outpt 2 of InitOrder ().
+}
+
+// This function works out the "init order" of the system in the background, just like
+// InitOrder (), and allows its progress to be followed while it runs, for example, by a
+// progress bar. The order hook of the system, if any, is called just like by
+// InitOrder ().
+//
+// Outpts
+//
+// outpt 0: A function telling how far the operation has gone, as a fraction from 0 to 1:
+// the share of the elements of the system already placed in the "init order". It could
+// be called at any time, from any goroutine. When the elements have priorities, or the
+// system has a comparator for ready elements, the elements are only placed once they are
+// all known to be ready, hence the fraction stays at 0 for most of the operation. If the
+// operation fails, the fraction stops where it was.
+//
+// outpt 1: A channel which delivers the outcome of the operation, once it is done, and is
+// then closed.
+func (someSystem *System) InitOrderWithProgress () (func () (float64), <-chan Result) {
+
+	var placed atomic.Int64
+	var total atomic.Int64
+	total.Store (-1) // Until the operation starts, the number of elements is unknown.
+	results := make (chan Result, 1)
+
+	go func () {
+		defer close (results)
+
+		someSystem.mutex.RLock ()
+		defer someSystem.mutex.RUnlock ()
+
+		total.Store (int64 (len (someSystem.systemElements)))
+		systemHook := someSystem.orderHook ()
+		hook := func (element string, index, count int) (error, string) {
+			placed.Store (int64 (index + 1))
+			if systemHook != nil {
+				return systemHook (element, index, count)
+			}
+			return nil, ""
+		}
+
+		initOrder, errX, errDescp := someSystem.initOrderContext (
+			context.Background (), hook)
+		results <- Result {initOrder, errX, errDescp}
+	} ()
+
+	progress := func () (float64) {
+		count := total.Load ()
+		switch {
+		case count < 0:
+			return 0
+		case count == 0:
+			return 1
+		}
+		return float64 (placed.Load ()) / float64 (count)
+	}
+	return progress, results
+}
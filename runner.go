@@ -0,0 +1,138 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Initializes the elements of the system, one after the other, in the "init order". The
+// "init order" is worked out before anything is initialized, so if the system has no
+// valid "init order", no element is initialized.
+//
+// Inputs
+//
+// input 0: The context of the operation. It is passed to every call of input 1, and if it
+// is done, no further element is initialized.
+//
+// input 1: The function that initializes an element. It is called once for each element,
+// with the ID of the element. Modifications of the system made while the elements are
+// being initialized have no effect on the operation, as the elements initialized are
+// those of the "init order" worked out at the start.
+//
+// Outpts
+//
+// outpt 0: If every element was initialized, value would be nil. If the "init order"
+// could not be worked out, value would be the error returned by InitOrderContext ().
+// Otherwise, value would be the error of the context, or the error of the first element
+// that failed to initialize, naming the element. No element is initialized after an
+// error.
+func (someSystem *System) Run (ctx context.Context, fn func (ctx context.Context,
+	id string) (error)) (error) {
+
+	initOrder, errX, _ := someSystem.InitOrderContext (ctx)
+	if errX != nil {
+		return errX
+	}
+
+	for _, element := range initOrder {
+		if errY := ctx.Err (); errY != nil {
+			return errY
+		}
+		if errZ := fn (ctx, element); errZ != nil {
+			return fmt.Errorf ("element '%s': %w", element, errZ)
+		}
+	}
+	return nil
+}
+
+// Initializes the elements of the system, level after level, in the levels provided by
+// InitLevels (). The elements of a level are initialized concurrently, and a level is
+// started only once every element of the level before it has been initialized. The
+// number of elements initialized at the same time could be limited using option
+// WithWorkers (). The levels are worked out before anything is initialized, so if the
+// system has no valid "init order", no element is initialized.
+//
+// Inputs
+//
+// input 0: The context of the operation. A context derived from it is passed to every call
+// of input 1, and if it is done, no further element is initialized.
+//
+// input 1: The function that initializes an element. It is called once for each element,
+// with the ID of the element, possibly by several goroutines at the same time.
+//
+// Outpts
+//
+// outpt 0: If every element was initialized, value would be nil. If the levels could not
+// be worked out, value would be the error returned by InitLevels (). If an element fails
+// to initialize, the context passed to input 1 is cancelled, the elements already being
+// initialized are waited for, and value would be the errors of all the elements that
+// failed, each naming its element, joined using errors.Join (). If the context of the
+// operation is done before the operation completes, value would be the error of the
+// context.
+func (someSystem *System) RunParallel (ctx context.Context, fn func (ctx context.Context,
+	id string) (error)) (error) {
+
+	levels, errX, _ := someSystem.InitLevels ()
+	if errX != nil {
+		return errX
+	}
+
+	runCtx, cancel := context.WithCancel (ctx)
+	defer cancel ()
+
+	workers := someSystem.options.workers
+	for _, level := range levels {
+		if errY := ctx.Err (); errY != nil {
+			return errY
+		}
+
+		// Declaration of some data to be used for initializing the level. { ...
+		var waitGroup sync.WaitGroup
+		var errsMutex sync.Mutex
+		errs := []error {}
+		var slots chan struct{} // Limits the elements initialized at the same time.
+		if workers > 0 {
+			slots = make (chan struct{}, workers)
+		}
+		// ... }
+
+		for _, element := range level {
+			if slots != nil {
+				slots <- struct{} {}
+			}
+			if runCtx.Err () != nil {
+				if slots != nil {
+					<- slots
+				}
+				break
+			}
+			waitGroup.Add (1)
+			go func (element string) {
+				defer waitGroup.Done ()
+				if slots != nil {
+					defer func () { <- slots } ()
+				}
+				if errZ := fn (runCtx, element); errZ != nil {
+					errsMutex.Lock ()
+					errs = append (errs, fmt.Errorf ("element '%s': %w", element,
+						errZ))
+					errsMutex.Unlock ()
+					cancel ()
+				}
+			} (element)
+		}
+		waitGroup.Wait ()
+
+		if len (errs) > 0 {
+			return errors.Join (errs...)
+		}
+		// If the context was done while the level was being initialized, some of its
+		// elements could have been skipped.
+		if errY := ctx.Err (); errY != nil {
+			return errY
+		}
+	}
+	return nil
+}
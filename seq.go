@@ -0,0 +1,71 @@
+package system
+
+import (
+	"context"
+	"iter"
+)
+
+// Provides the "init order" of the system as a sequence, for use with range-over-func.
+// Elements are yielded as soon as their place in the "init order" is known, so the
+// consumer could start initializing the first elements, before the whole "init order"
+// has been worked out. Breaking out of the loop stops the operation.
+//
+// The sequence yields pairs of an element and a nil error. If an error, like those of
+// InitOrder (), is encountered, a final pair of an empty string and the error is yielded,
+// and the sequence ends. Hence, elements yielded before the error should not be trusted,
+// as the system as a whole has no valid "init order".
+//
+// The sequence works on a copy of the system, taken when the sequence is created, so the
+// system could be modified (even by the consumer of the sequence), without affecting the
+// sequence.
+func (someSystem *System) InitOrderSeq () (iter.Seq2[string, error]) {
+
+	snapshot := someSystem.Clone ()
+
+	return func (yield func (string, error) (bool)) {
+
+		/* Preferences, like priorities, can only be taken into account once every
+			element is known to be ready, hence the whole "init order" is worked out
+			first. */
+		if snapshot.hasPreferences () == true {
+			initOrder, errX, _ := snapshot.initOrder ()
+			if errX != nil {
+				yield ("", errX)
+				return
+			}
+			for _, orderedElement := range initOrder {
+				if yield (orderedElement, nil) == false {
+					return
+				}
+			}
+			return
+		}
+
+		initOrder := []string {}
+		ordered := map[string]bool {}
+		depthOf := map[string]int {}
+		for _, element := range snapshot.systemElements {
+			if ordered [element] == true {
+				continue
+			}
+			alreadyYielded := len (initOrder)
+			var errX error
+			initOrder, errX, _ = addToInitOrder (context.Background (), initOrder,
+				ordered, element, snapshot)
+			if errX != nil {
+				yield ("", errX)
+				return
+			}
+			for _, orderedElement := range initOrder [alreadyYielded:] {
+				if errY, _ := snapshot.checkDepth (orderedElement, depthOf); errY !=
+					nil {
+					yield ("", errY)
+					return
+				}
+				if yield (orderedElement, nil) == false {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Creates a new system from Go structs, reading the dependencies of each element from the
+// "deps" tags of the fields of its struct. A tag lists IDs separated by commas, and every
+// field tagged adds its IDs to the dependencies; a blank field is a handy place for the
+// tag:
+//
+// 	type Web struct {
+// 		_ struct{} `deps:"db,cache"`
+// 		Port int
+// 	}
+//
+// A struct without any "deps" tag has no dependency. Elements are added in the order of
+// their IDs, each just like with AddElement (), just like with AddElements ().
+//
+// Inputs
+//
+// input 0: The structs, where the key of each record is the ID of an element. A value
+// could be a struct, or a pointer to one.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error occurs, value would be nil.
+//
+// outpt 1: If any element could not be added, value would be the errors of all such
+// elements, just like in AddElements (). A value that is not a struct is also reported
+// this way.
+func FromStructs (items map[string]any) (*System, error) {
+
+	ids := make ([]string, 0, len (items))
+	for id := range items {
+		ids = append (ids, id)
+	}
+	sort.Strings (ids)
+
+	errs := []error {}
+	dependencies := map[string][]string {}
+	for _, id := range ids {
+		deps, errX := structDependencies (items [id])
+		if errX != nil {
+			errs = append (errs, fmt.Errorf ("element '%s': %w", id, errX))
+			continue
+		}
+		dependencies [id] = deps
+	}
+	if len (errs) > 0 {
+		return nil, errors.Join (errs...)
+	}
+
+	newSystem := New ()
+	if errY := newSystem.AddElements (dependencies); errY != nil {
+		return nil, errY
+	}
+	return newSystem, nil
+}
+
+func structDependencies (item any) ([]string, error) { /* This function reads the
+	dependencies listed in the "deps" tags of the fields of a struct, in the order of
+	the fields. */
+
+	value := reflect.ValueOf (item)
+	for value.Kind () == reflect.Pointer && value.IsNil () == false {
+		value = value.Elem ()
+	}
+	if value.Kind () != reflect.Struct {
+		return nil, fmt.Errorf ("The value is of type %T, rather than a struct.", item)
+	}
+
+	deps := []string {}
+	valueType := value.Type ()
+	for index := 0; index < valueType.NumField (); index ++ {
+		tag, okX := valueType.Field (index).Tag.Lookup ("deps")
+		if okX == false {
+			continue
+		}
+		for _, dep := range strings.Split (tag, ",") {
+			if dep = strings.TrimSpace (dep); dep != "" {
+				deps = append (deps, dep)
+			}
+		}
+	}
+	return deps, nil
+}
@@ -1,121 +1,2085 @@
 package system
 
 import (
+	"container/heap"
+	"context"
 	"errors"
 	"fmt"
 	"gopkg.in/qamarian-etc/slices.v1"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
-func New () (*System) { // Creates a new system.
-	return &System {[]string {}, map[string][]string {}, ""}
+// Creates a new system. Options could be given to change how the system behaves; without
+// any option, the system behaves just like it always has.
+func New (someOptions ...Option) (*System) {
+	newSystem := &System {systemElements: []string {}, dependencies: map[string][]string {},
+		addedElements: map[string]struct{} {}, dependents: map[string][]string {},
+		tags: map[string]string {}, optionalDependencies: map[string][]string {},
+		metadata: map[string]any {}, priorities: map[string]int {}}
+	for _, someOption := range someOptions {
+		someOption (&newSystem.options)
+	}
+	return newSystem
+}
+
+// An option changes how a system behaves. Options are given to New ().
+type Option func (*options)
+
+type options struct { // The options of a system.
+	strictDeps bool // Tells if dependencies must be in the system, before they are given.
+	workers int /* The most elements RunParallel () initializes at the same time. Value 0
+		means there is no limit. */
+	idValidator func (string) (error) // Checks IDs, before they are used.
+	maxDepth int /* The greatest depth an element may have in the "init order". Value 0
+		means there is no limit. */
+	orderHook func (string, int, int) /* Called by InitOrder () as each element is
+		placed in the "init order". */
+	readyLess func (string, string) (bool) /* Decides which of the ready elements comes
+		next in the "init order". */
+}
+
+// Makes a system strict about dependencies: a dependency given to AddElement (),
+// UpdateDependencies (), or AddDependency () must already be in the system, otherwise
+// error ErrElementMissing is returned, at once. This way, a missing dependency is noticed
+// when it is given, rather than later, when the "init order" is worked out.
+//
+// With this option, the order in which elements are added matters: an element must be
+// added after all its dependencies.
+func WithStrictDeps () (Option) {
+	return func (someOptions *options) {
+		someOptions.strictDeps = true
+	}
+}
+
+// Limits the number of elements RunParallel () initializes at the same time. Value 0, the
+// default, means there is no limit.
+func WithWorkers (n int) (Option) {
+	return func (someOptions *options) {
+		someOptions.workers = n
+	}
+}
+
+// Makes a system check every ID given to AddElement (), UpdateDependencies (), and
+// AddDependency (), whether the ID of an element or of a dependency, using a function. If
+// the function returns an error for an ID, the operation fails with that error. This
+// could be used to enforce a naming convention for IDs. The empty string is rejected, as
+// always, before the function is called.
+func WithIDValidator (validator func (id string) (error)) (Option) {
+	return func (someOptions *options) {
+		someOptions.idValidator = validator
+	}
+}
+
+// Limits how long chains of dependencies could be. InitOrder (), and every operation
+// based on it, fails with error ErrMaxDepthExceeded, if the depth of an element in the
+// "init order" (see Depths ()) is greater than n. This could be used to catch systems in
+// which elements are coupled far more than intended. Value 0, the default, means there is
+// no limit.
+func WithMaxDepth (n int) (Option) {
+	return func (someOptions *options) {
+		someOptions.maxDepth = n
+	}
+}
+
+// Makes InitOrder () and InitOrderContext () call a function, as each element is placed in
+// the "init order", for example, to report the progress of the operation on a very large
+// system. The function is given the ID of the element, its position in the "init order"
+// (starting from 0), and the number of elements in the system. If the operation fails
+// part of the way, the elements already reported should not be trusted.
+//
+// The system is unlocked while the function is called, so other operations could use the
+// system in the meantime. However, if the system is modified before the function returns,
+// whether by the function itself or by anything else, the "init order" being worked out
+// could no longer be trusted: the operation stops, and fails with error
+// ErrConcurrentModification.
+func WithOrderHook (hook func (id string, index, total int)) (Option) {
+	return func (someOptions *options) {
+		someOptions.orderHook = hook
+	}
+}
+
+// Makes InitOrder (), and every operation based on it, use a function to decide which
+// element comes next, whenever more than one element could come next in the "init order",
+// for example, to have databases come before caches, without adding dependencies. The
+// function tells if element "a" should come before element "b"; when it reports neither
+// should come first, the one of the two added to the system earlier comes first.
+// Dependencies are still always respected.
+//
+// With this option, the "init order" is built by repeatedly picking, among the elements
+// whose dependencies are all already placed, the one that should come first. This is not
+// how the "init order" is built without this option: elements are then taken in the
+// order they were added, each placed right after its dependencies. Hence, even a function
+// that never reports a preference could give a different "init order" than the default
+// one. For example, if element "a", depending on "c", is added, then "b", then "c", the
+// default "init order" is [c a b], but with this option, it is [b c a], as "b" and "c"
+// are the elements that could come first, and "b" was added earlier.
+//
+// When elements have priorities (see AddElementPrio ()), priorities are compared first,
+// and the function only decides between elements with the same priority.
+//
+// The function is called while the system is locked, so it must not call any method of
+// the system, not even one that only reads it, like Meta (): a method modifying the
+// system would block forever, and one reading it could block forever too, whenever
+// another goroutine is waiting to modify the system. Whatever the function needs to know
+// about elements, like their metadata, should be gathered before the "init order" is
+// worked out.
+func WithReadyComparator (less func (a, b string) (bool)) (Option) {
+	return func (someOptions *options) {
+		someOptions.readyLess = less
+	}
+}
+
+// A system is safe for concurrent use by multiple goroutines. Just like when used by a
+// single goroutine, a system must be created using New (); the zero value of this type is
+// not usable.
+type System struct {
+	systemElements []string // All elements in the system.
+	dependencies map[string][]string /* The dependencies of individual elements in the
+		system. The list of dependencies of each individual element, would be
+		stored in this hash map, where the key of each record would be the ID of
+		the element. Every element in the system has a record, and the list is
+		never nil: an element without dependencies has an empty list. A list is
+		never modified once stored, but replaced (see setDependencies ()), so it
+		could be used after the lock of the system is released. */
+	addedElements map[string]struct{} /* A set that keeps track of what elements have
+		been added to the system. It is just a redundant data meant to help speed
+		up some certain operations of this data type. */
+	dependents map[string][]string /* The reverse of the dependencies: the elements that
+		depend on an ID, where the key of each record would be the ID depended on.
+		Just like the set of added elements, it is a redundant data, kept in line
+		with the dependencies whenever they change, so the dependents of an
+		element could be looked up, rather than searched for. IDs not in the
+		system could also have records. */
+	tags map[string]string /* The tags of the elements that have one, where the key of
+		each record would be the ID of the element. */
+	optionalDependencies map[string][]string /* The optional dependencies of the
+		elements that have some, where the key of each record would be the ID of
+		the element. */
+	metadata map[string]any /* The data attached to the elements that have some, where
+		the key of each record would be the ID of the element. */
+	priorities map[string]int /* The priorities of the elements that have one, where the
+		key of each record would be the ID of the element. */
+	options options // The options given when the system was created.
+	frozen bool // Tells if the system has been frozen, using Freeze ().
+	cache map[string][]string /* The "init order" of each element and its
+		dependencies, as worked out by some earlier operation, where the key of
+		each record would be the ID of the element. It is emptied whenever the
+		system is modified. */
+	cacheSize int // The number of IDs in the cache, in total.
+	modifications uint64 /* The number of times the system has been modified. It is
+		used to notice modifications made while an operation had the system
+		unlocked. */
+	cacheMutex sync.Mutex /* Operations that only read the system could share its lock,
+		hence the cache has a lock of its own. */
+	mutex sync.RWMutex /* Modifications of the system hold this lock for writing, while
+		every other operation holds it for reading. */
+}
+
+// Adds an element to the system.
+//
+// Inputs
+//
+// input 0: The new element to be added to the system. Value can not be an empty string.
+//
+// input 1: The IDs of the dependencies of the element. The ID of a dependency may not be
+// an empty string, nor the ID of the element itself. A dependency given more than once is
+// stored only once.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrAlreadyAdded (as *AlreadyAddedError),
+// ErrSelfDependency, and, when the system is strict about dependencies,
+// ErrElementMissing. Errors of the ID validator of the system, if any, are also returned.
+func (someSystem *System) AddElement (newElement string, dependencies []string) (error) {
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+	return someSystem.addElement (newElement, dependencies)
+}
+
+func (someSystem *System) addElement (newElement string, dependencies []string) (error) { /*
+	This function is the lock-free version of AddElement (), and is meant to be used by
+	operations that already hold the lock of the system for writing. */
+
+	if newElement == "" {
+		return errors.New ("Empty string can not be used as ID of an element.")
+	}
+	if someSystem.options.idValidator != nil {
+		if errX := someSystem.options.idValidator (newElement); errX != nil {
+			return errX
+		}
+	}
+	deps, errX := someSystem.prepareDependencies (newElement, dependencies, false)
+	if errX != nil {
+		return errX
+	}
+	if _, okX := someSystem.addedElements [newElement]; okX == true {
+		existing := make ([]string, len (someSystem.dependencies [newElement]))
+		copy (existing, someSystem.dependencies [newElement])
+		return &AlreadyAddedError {newElement, existing}
+	}
+	someSystem.touch ()
+	someSystem.systemElements = append (someSystem.systemElements, newElement)
+	someSystem.setDependencies (newElement, deps)
+	someSystem.addedElements [newElement] = struct{} {}
+	return nil
+}
+
+// Adds many elements to the system at once. Elements are added in the order of their
+// IDs, each just like with AddElement (). An element that could not be added does not
+// stop the others from being added.
+//
+// Inputs
+//
+// input 0: The elements to be added, where the key of each record is the ID of an
+// element, and the value is the IDs of its dependencies.
+//
+// Outpts
+//
+// outpt 0: If every element was added, value would be nil. Otherwise, value would be the
+// errors of all the elements that could not be added, joined using errors.Join (). Each
+// of those errors names its element, and wraps the error returned by AddElement ().
+func (someSystem *System) AddElements (elements map[string][]string) (error) {
+
+	ids := make ([]string, 0, len (elements))
+	for id := range elements {
+		ids = append (ids, id)
+	}
+	sort.Strings (ids)
+
+	errs := []error {}
+	for _, id := range ids {
+		if errX := someSystem.AddElement (id, elements [id]); errX != nil {
+			errs = append (errs, fmt.Errorf ("element '%s': %w", id, errX))
+		}
+	}
+	return errors.Join (errs...)
+}
+
+// Copies every element of another system, with its dependencies, into the system.
+// Elements are copied in the order they were added to the other system, each checked
+// just like with AddElementOpt (), so the ID validator of the system, and its
+// strictness about dependencies, apply to them. An element in both systems is left as
+// it is, provided it has the same dependencies, and the same optional dependencies, in
+// both (the order of the dependencies does not matter). If any element has different
+// dependencies, or different optional dependencies, in the two systems, or could not be
+// added to the system, nothing is copied.
+//
+// Inputs
+//
+// input 0: The system whose elements should be copied. It is not modified.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrConflict, and the errors of AddElementOpt (),
+// naming the element that could not be copied.
+func (someSystem *System) Merge (other *System) (error) {
+
+	snapshot := other.Clone () /* A copy is used, so the lock of the other system is not
+		needed while the lock of this system is held. */
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	// Checking for conflicts, before anything is copied.
+	for _, element := range snapshot.systemElements {
+		if someSystem.hasElement (element) == false {
+			continue
+		}
+		existing := someSystem.dependencies [element]
+		incoming := snapshot.dependencies [element]
+		if sameDependencies (existing, incoming) == false {
+			return fmt.Errorf ("%w: element '%s' depends on %v in the system, but on " +
+				"%v in the other system", ErrConflict, element, existing, incoming)
+		}
+		existingOpt := someSystem.optionalDependencies [element]
+		incomingOpt := snapshot.optionalDependencies [element]
+		if sameDependencies (existingOpt, incomingOpt) == false {
+			return fmt.Errorf ("%w: element '%s' optionally depends on %v in the " +
+				"system, but on %v in the other system", ErrConflict, element,
+				existingOpt, incomingOpt)
+		}
+	}
+
+	/* Each element is checked just like AddElementOpt () would check it, right before
+		it is copied. If an element fails the check, the elements already copied are
+		removed, so nothing is copied. */
+	copied := []string {}
+	for _, element := range snapshot.systemElements {
+		if someSystem.hasElement (element) == true {
+			continue
+		}
+		errX := someSystem.checkElement (element, snapshot.dependencies [element],
+			snapshot.optionalDependencies [element])
+		if errX != nil {
+			for _, copiedElement := range copied {
+				someSystem.removeElement (copiedElement)
+			}
+			return fmt.Errorf ("element '%s': %w", element, errX)
+		}
+		someSystem.copyElement (snapshot, element)
+		copied = append (copied, element)
+	}
+	return nil
+}
+
+func (someSystem *System) checkElement (element string, dependencies,
+	optionalDependencies []string) (error) { /* This function checks if an element, not
+	yet in the system, could be added to the system with some dependencies and
+	optional dependencies, just like AddElementOpt () would: the IDs must be accepted
+	by the ID validator of the system, if any, and when the system is strict about
+	dependencies, the dependencies must be in the system. It is meant to be used by
+	operations that already hold the lock of the system. */
+
+	if element == "" {
+		return errors.New ("Empty string can not be used as ID of an element.")
+	}
+	if someSystem.options.idValidator != nil {
+		if errX := someSystem.options.idValidator (element); errX != nil {
+			return errX
+		}
+	}
+	_, errY := someSystem.prepareDependencies (element, dependencies, false)
+	if errY != nil {
+		return errY
+	}
+	_, errZ := someSystem.prepareDependencies (element, optionalDependencies, true)
+	return errZ
+}
+
+func sameDependencies (someDeps, otherDeps []string) (bool) { /* This function tells if
+	two lists of dependencies, each free of repetitions, have the same dependencies,
+	regardless of their order. */
+	if len (someDeps) != len (otherDeps) {
+		return false
+	}
+	for _, dep := range someDeps {
+		if slices.IsElementInStringSlice (otherDeps, dep) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// Replaces the dependencies of an element already in the system.
+//
+// Inputs
+//
+// input 0: The ID of the element whose dependencies should be replaced.
+//
+// input 1: The IDs of the new dependencies of the element. The ID of a dependency may not
+// be an empty string, nor the ID of the element itself. A dependency given more than once
+// is stored only once. The system keeps its own copy of this slice.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrElementNotFound, ErrSelfDependency, and, when the
+// system is strict about dependencies, ErrElementMissing. Errors of the ID validator of
+// the system, if any, are also returned.
+func (someSystem *System) UpdateDependencies (element string, dependencies []string) (
+	error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if someSystem.hasElement (element) == false {
+		return ErrElementNotFound
+	}
+	deps, errX := someSystem.prepareDependencies (element, dependencies, false)
+	if errX != nil {
+		return errX
+	}
+	someSystem.setDependencies (element, deps)
+	return nil
+}
+
+// Adds a dependency to an element already in the system. If the element already has the
+// dependency, nothing is done.
+//
+// Inputs
+//
+// input 0: The ID of the element.
+//
+// input 1: The ID of the new dependency. Value can not be an empty string, nor the ID of
+// the element itself.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrElementNotFound, ErrSelfDependency, and, when the
+// system is strict about dependencies, ErrElementMissing. Errors of the ID validator of
+// the system, if any, are also returned.
+func (someSystem *System) AddDependency (element, dependency string) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if someSystem.hasElement (element) == false {
+		return ErrElementNotFound
+	}
+	_, errX := someSystem.prepareDependencies (element, []string {dependency}, false)
+	if errX != nil {
+		return errX
+	}
+	deps := someSystem.dependencies [element]
+	if slices.IsElementInStringSlice (deps, dependency) == true {
+		return nil
+	}
+	newDeps := make ([]string, len (deps), len (deps) + 1)
+	copy (newDeps, deps)
+	someSystem.setDependencies (element, append (newDeps, dependency))
+	return nil
+}
+
+// Removes a dependency of an element already in the system. If the element does not have
+// the dependency, nothing is done.
+//
+// Inputs
+//
+// input 0: The ID of the element.
+//
+// input 1: The ID of the dependency to be removed.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrElementNotFound.
+func (someSystem *System) RemoveDependency (element, dependency string) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if someSystem.hasElement (element) == false {
+		return ErrElementNotFound
+	}
+	deps := someSystem.dependencies [element]
+	index := slices.IndexInStringSlice (deps, dependency)
+	if index == -1 {
+		return nil
+	}
+	newDeps := make ([]string, 0, len (deps) - 1)
+	newDeps = append (newDeps, deps [:index]...)
+	someSystem.setDependencies (element, append (newDeps, deps [index + 1:]...))
+	return nil
+}
+
+func (someSystem *System) setDependencies (element string, dependencies []string) { /*
+	This function sets the dependencies of an element, keeping the index of dependents
+	in line with them. It is meant to be used by operations that already hold the lock
+	of the system for writing. */
+	someSystem.touch ()
+	someSystem.dropDependencies (element)
+	someSystem.dependencies [element] = dependencies
+	for _, dep := range dependencies {
+		someSystem.dependents [dep] = append (someSystem.dependents [dep], element)
+	}
+}
+
+func (someSystem *System) dropDependencies (element string) { /* This function removes
+	the record of the dependencies of an element, keeping the index of dependents in
+	line with it. It is meant to be used by operations that already hold the lock of the
+	system for writing. */
+	someSystem.touch ()
+	for _, dep := range someSystem.dependencies [element] {
+		dependents := slices.RemoveFromStringSlice (someSystem.dependents [dep], element)
+		if len (dependents) == 0 {
+			delete (someSystem.dependents, dep)
+			continue
+		}
+		someSystem.dependents [dep] = dependents
+	}
+	delete (someSystem.dependencies, element)
+}
+
+func (someSystem *System) prepareDependencies (element string, dependencies []string,
+	optional bool) ([]string, error) { /* This function checks if the IDs of some
+	dependencies could be used as the dependencies of an element. If they could, a copy
+	of the dependencies, free of repetitions, is returned, for the system to store. The
+	first appearance of each dependency decides its position in the copy. Optional
+	dependencies need not be in the system, even when the system is strict about
+	dependencies. */
+
+	deps := make ([]string, 0, len (dependencies))
+	seen := map[string]bool {}
+	for _, dep := range dependencies {
+		if dep == "" {
+			return nil, errors.New ("The ID of a dependency is an empty string.")
+		}
+		if someSystem.options.idValidator != nil {
+			if errX := someSystem.options.idValidator (dep); errX != nil {
+				return nil, errX
+			}
+		}
+		if dep == element {
+			return nil, fmt.Errorf ("%w: element '%s' lists itself as a " +
+				"dependency", ErrSelfDependency, element)
+		}
+		if seen [dep] == true {
+			continue
+		}
+		if someSystem.options.strictDeps == true && optional == false &&
+			someSystem.hasElement (dep) == false {
+			return nil, &MissingDependencyError {element, dep, someSystem.chainTo (
+				element, dep)}
+		}
+		seen [dep] = true
+		deps = append (deps, dep)
+	}
+	return deps, nil
+}
+
+// Tells if an element is in the system. The check is exact, so an ID that is only a part
+// of another ID would not be mistaken for it.
+func (someSystem *System) HasElement (element string) (bool) {
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+	return someSystem.hasElement (element)
+}
+
+func (someSystem *System) hasElement (element string) (bool) { /* This function is the
+	lock-free version of HasElement (), and is meant to be used by operations that
+	already hold the lock of the system. */
+	_, okX := someSystem.addedElements [element]
+	return okX
+}
+
+// Removes an element from the system.
+//
+// Inputs
+//
+// input 0: The ID of the element to be removed.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrElementNotFound.
+//
+// Elements that depend on the removed element are left untouched, hence InitOrder () would
+// report the removed element as a missing dependency, until they are also removed.
+func (someSystem *System) RemoveElement (element string) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if someSystem.hasElement (element) == false {
+		return ErrElementNotFound
+	}
+	someSystem.removeElement (element)
+	return nil
+}
+
+// Removes an element from the system, and rewires the elements that depend on it: in the
+// dependencies of each of those elements, the removed element is replaced with its own
+// dependencies. This way, the dependents of the removed element still come after its
+// dependencies in the "init order", and no dependency goes missing.
+//
+// Inputs
+//
+// input 0: The ID of the element to be removed.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrElementNotFound.
+func (someSystem *System) RemoveElementAndRewire (element string) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if someSystem.hasElement (element) == false {
+		return ErrElementNotFound
+	}
+
+	/* A copy is used, as the record of the dependents of the element changes while the
+		dependents are rewired. */
+	dependents := make ([]string, len (someSystem.dependents [element]))
+	copy (dependents, someSystem.dependents [element])
+	for _, dependent := range dependents {
+		newDeps := []string {}
+		for _, dep := range someSystem.dependencies [dependent] {
+			if dep != element {
+				newDeps = append (newDeps, dep)
+				continue
+			}
+			newDeps = append (newDeps, someSystem.dependencies [element]...)
+		}
+
+		/* Repetitions are removed, and so is the dependent itself, which is among
+			the dependencies of the removed element, if the two formed a circle. */
+		rewiredDeps := []string {}
+		for _, dep := range newDeps {
+			if dep != dependent && slices.IsElementInStringSlice (rewiredDeps,
+				dep) == false {
+				rewiredDeps = append (rewiredDeps, dep)
+			}
+		}
+		someSystem.setDependencies (dependent, rewiredDeps)
+	}
+	someSystem.removeElement (element)
+	return nil
+}
+
+func (someSystem *System) removeElement (element string) { /* This function removes an
+	element known to be in the system. It is meant to be used by operations that
+	already hold the lock of the system for writing. */
+	someSystem.touch ()
+	someSystem.systemElements = slices.RemoveFromStringSlice (someSystem.systemElements,
+		element)
+	someSystem.dropDependencies (element)
+	delete (someSystem.addedElements, element)
+	delete (someSystem.tags, element)
+	delete (someSystem.optionalDependencies, element)
+	delete (someSystem.metadata, element)
+	delete (someSystem.priorities, element)
+}
+
+// Changes the ID of an element already in the system. Wherever the element is a
+// dependency, or an optional dependency, of another element, its old ID is replaced with
+// its new ID, so the graph stays the same. The position of the element in the order in
+// which elements were added, its tag, and its metadata are kept.
+//
+// Inputs
+//
+// input 0: The current ID of the element.
+//
+// input 1: The new ID of the element. Value can not be an empty string, nor the ID of an
+// element already in the system.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrElementNotFound, ErrAlreadyAdded (as
+// *AlreadyAddedError), and ErrSelfDependency, when the element itself has a dependency
+// with the new ID. Errors of the ID validator of the system, if any, are also returned.
+func (someSystem *System) Rename (oldID, newID string) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if someSystem.hasElement (oldID) == false {
+		return ErrElementNotFound
+	}
+	if oldID == newID {
+		return nil
+	}
+	if newID == "" {
+		return errors.New ("Empty string can not be used as ID of an element.")
+	}
+	if someSystem.options.idValidator != nil {
+		if errX := someSystem.options.idValidator (newID); errX != nil {
+			return errX
+		}
+	}
+	if someSystem.hasElement (newID) == true {
+		existing := make ([]string, len (someSystem.dependencies [newID]))
+		copy (existing, someSystem.dependencies [newID])
+		return &AlreadyAddedError {newID, existing}
+	}
+	if slices.IsElementInStringSlice (someSystem.dependencies [oldID], newID) == true ||
+		slices.IsElementInStringSlice (someSystem.optionalDependencies [oldID],
+		newID) == true {
+		return fmt.Errorf ("%w: element '%s' lists itself as a dependency",
+			ErrSelfDependency, newID)
+	}
+
+	someSystem.touch ()
+	index := slices.IndexInStringSlice (someSystem.systemElements, oldID)
+	someSystem.systemElements [index] = newID
+	delete (someSystem.addedElements, oldID)
+	someSystem.addedElements [newID] = struct{} {}
+	deps := someSystem.dependencies [oldID]
+	someSystem.dropDependencies (oldID)
+	someSystem.setDependencies (newID, deps)
+	if tag, okX := someSystem.tags [oldID]; okX == true {
+		delete (someSystem.tags, oldID)
+		someSystem.tags [newID] = tag
+	}
+	if optionalDeps, okX := someSystem.optionalDependencies [oldID]; okX == true {
+		delete (someSystem.optionalDependencies, oldID)
+		someSystem.optionalDependencies [newID] = optionalDeps
+	}
+	if meta, okX := someSystem.metadata [oldID]; okX == true {
+		delete (someSystem.metadata, oldID)
+		someSystem.metadata [newID] = meta
+	}
+	if priority, okX := someSystem.priorities [oldID]; okX == true {
+		delete (someSystem.priorities, oldID)
+		someSystem.priorities [newID] = priority
+	}
+
+	/* A copy is used, as the record of the dependents of the element changes while the
+		dependents are updated. */
+	dependents := make ([]string, len (someSystem.dependents [oldID]))
+	copy (dependents, someSystem.dependents [oldID])
+	for _, dependent := range dependents {
+		someSystem.setDependencies (dependent, renameDependency (
+			someSystem.dependencies [dependent], oldID, newID))
+	}
+	for element, optionalDeps := range someSystem.optionalDependencies {
+		if slices.IsElementInStringSlice (optionalDeps, oldID) == true {
+			someSystem.optionalDependencies [element] = renameDependency (
+				optionalDeps, oldID, newID)
+		}
+	}
+	return nil
+}
+
+func renameDependency (dependencies []string, oldID, newID string) ([]string) { /* This
+	function returns a copy of some dependencies, with an ID replaced by another. If the
+	other ID is already among the dependencies, only its first appearance is kept. */
+	renamed := make ([]string, 0, len (dependencies))
+	for _, dep := range dependencies {
+		if dep == oldID {
+			dep = newID
+		}
+		if slices.IsElementInStringSlice (renamed, dep) == false {
+			renamed = append (renamed, dep)
+		}
+	}
+	return renamed
+}
+
+// Puts the system in a canonical form: the elements are sorted by ID, as if they had been
+// added in that order, and so are the dependencies, and the optional dependencies, of
+// every element. Systems that are equal (see Equal ()) have exactly the same canonical
+// form, so their encodings, like those of MarshalJSON () and ToYAML (), are the same,
+// whatever the order in which elements and dependencies were given.
+//
+// Every element still depends on exactly what it did, so the "init order" still respects
+// every dependency. However, since ties in the "init order" are broken using the order in
+// which elements were added, and the order of their dependencies, the "init order" may
+// change to another, equally valid, one.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrFrozen.
+func (someSystem *System) Canonicalize () (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	someSystem.touch ()
+	sort.Strings (someSystem.systemElements)
+	for _, element := range someSystem.systemElements {
+		deps := make ([]string, len (someSystem.dependencies [element]))
+		copy (deps, someSystem.dependencies [element])
+		sort.Strings (deps)
+		someSystem.setDependencies (element, deps)
+		sort.Strings (someSystem.optionalDependencies [element])
+	}
+	return nil
+}
+
+// Removes all the elements of the system, leaving it just like a system newly created
+// using New ().
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrFrozen.
+func (someSystem *System) Clear () (error) {
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+	someSystem.adopt (New ())
+	return nil
+}
+
+// Makes the system read-only. Afterwards, every operation that would modify the system,
+// Clear () included, fails with error ErrFrozen, and leaves the system as it is.
+// Operations that only read the system, like InitOrder (), keep working. A system can not
+// be unfrozen, but a copy made using Clone () is not frozen.
+func (someSystem *System) Freeze () {
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+	someSystem.frozen = true
+}
+
+// Tells if the system has been frozen, using Freeze ().
+func (someSystem *System) IsFrozen () (bool) {
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+	return someSystem.frozen
+}
+
+func (someSystem *System) adopt (other *System) { /* This function makes the system take
+	over the data of another system, which must not be used afterwards. It is meant to
+	be used by operations that already hold the lock of the system for writing. */
+	someSystem.touch ()
+	someSystem.systemElements = other.systemElements
+	someSystem.dependencies = other.dependencies
+	someSystem.addedElements = other.addedElements
+	someSystem.dependents = other.dependents
+	someSystem.tags = other.tags
+	someSystem.optionalDependencies = other.optionalDependencies
+	someSystem.metadata = other.metadata
+	someSystem.priorities = other.priorities
+}
+
+// Returns the IDs of all the elements in the system, in the order they were added. The
+// slice returned is a copy, so modifying it would not affect the system.
+func (someSystem *System) Elements () ([]string) {
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+	elements := make ([]string, len (someSystem.systemElements))
+	copy (elements, someSystem.systemElements)
+	return elements
+}
+
+// Returns the number of elements in the system.
+func (someSystem *System) Len () (int) {
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+	return len (someSystem.systemElements)
+}
+
+// Tells if the system has no element.
+func (someSystem *System) IsEmpty () (bool) {
+	return someSystem.Len () == 0
+}
+
+// Returns the number of dependencies in the system, that is, the total of the number of
+// dependencies of every element. Dependencies not in the system are counted too.
+func (someSystem *System) EdgeCount () (int) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	count := 0
+	for _, deps := range someSystem.dependencies {
+		count += len (deps)
+	}
+	return count
+}
+
+// Returns the dependencies of an element.
+//
+// Inputs
+//
+// input 0: The ID of the element whose dependencies are needed.
+//
+// Outpts
+//
+// outpt 0: The IDs of the dependencies of the element. The slice returned is a copy, so
+// modifying it would not affect the system. It is never nil for an element in the
+// system: an element without dependencies, including one added with nil dependencies,
+// gives an empty slice. If an error occurs, value would be nil.
+//
+// outpt 1: Possible errors include: ErrElementNotFound.
+func (someSystem *System) Dependencies (element string) ([]string, error) {
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+	deps, okX := someSystem.dependencies [element]
+	if okX == false {
+		return nil, ErrElementNotFound
+	}
+	depsCopy := make ([]string, len (deps))
+	copy (depsCopy, deps)
+	return depsCopy, nil
+}
+
+// Calls a function for each dependency of an element, in the order of the dependencies,
+// without copying them, unlike Dependencies (). This suits code that looks at the
+// dependencies of many elements, and would otherwise create many short-lived slices.
+//
+// The function is called with the system unlocked, so it could use the system, even to
+// modify it. The dependencies the function is called with are those the element had
+// when this operation started, as the system never changes a list of dependencies once
+// stored, but replaces it.
+//
+// Inputs
+//
+// input 0: The ID of the element whose dependencies are needed.
+//
+// input 1: The function to be called with the ID of each dependency. If it returns false,
+// it is not called for the remaining dependencies.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: ErrElementNotFound.
+func (someSystem *System) RangeDependencies (element string, fn func (dep string) (bool)) (
+	error) {
+
+	someSystem.mutex.RLock ()
+	deps, okX := someSystem.dependencies [element]
+	someSystem.mutex.RUnlock ()
+
+	if okX == false {
+		return ErrElementNotFound
+	}
+	for _, dep := range deps {
+		if fn (dep) == false {
+			break
+		}
+	}
+	return nil
+}
+
+// Returns the elements that directly depend on an element.
+//
+// Inputs
+//
+// input 0: The ID of the element whose dependents are needed.
+//
+// Outpts
+//
+// outpt 0: The IDs of the elements that list the element as a dependency, in the order
+// they came to depend on it. The slice returned is a copy, so modifying it would not
+// affect the system. If an error occurs, value would be nil.
+//
+// outpt 1: Possible errors include: ErrElementNotFound.
+func (someSystem *System) Dependents (element string) ([]string, error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if someSystem.hasElement (element) == false {
+		return nil, ErrElementNotFound
+	}
+	dependents := make ([]string, len (someSystem.dependents [element]))
+	copy (dependents, someSystem.dependents [element])
+	return dependents, nil
+}
+
+// Returns the elements that directly depend on at least one of some IDs, for example, to
+// find every consumer of a group of elements about to be removed. Unlike Dependents (),
+// the IDs need not be in the system, so the dependents of missing dependencies could also
+// be found.
+//
+// Inputs
+//
+// input 0: The IDs whose dependents are needed.
+//
+// Outpts
+//
+// outpt 0: The IDs of the elements that list any of the IDs as a dependency. The IDs are
+// sorted, and each appears once.
+func (someSystem *System) ElementsDependingOnAny (deps []string) ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	found := map[string]bool {}
+	for _, dep := range deps {
+		for _, dependent := range someSystem.dependents [dep] {
+			found [dependent] = true
+		}
+	}
+	elements := make ([]string, 0, len (found))
+	for element := range found {
+		elements = append (elements, element)
+	}
+	sort.Strings (elements)
+	return elements
+}
+
+// Returns the elements in a namespace, for systems whose IDs are qualified with the
+// namespaces of their elements, like "groupA.service1". An element is in a namespace if
+// its ID is the name of the namespace, followed by a dot, and anything else, so
+// "groupA.service1" and "groupA.sub.service2" are both in namespace "groupA", while
+// "groupAB.service3" is not. Dots have no other meaning to the system: IDs are always
+// compared exactly, so dotted IDs could never be mistaken for one another.
+//
+// Inputs
+//
+// input 0: The name of the namespace, like "groupA". A trailing dot is ignored. If value is
+// an empty string, every element is in the namespace.
+//
+// Outpts
+//
+// outpt 0: The IDs of the elements in the namespace. The IDs are sorted.
+func (someSystem *System) ElementsInNamespace (prefix string) ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	prefix = strings.TrimSuffix (prefix, ".")
+	elements := []string {}
+	for _, element := range someSystem.systemElements {
+		if prefix == "" || strings.HasPrefix (element, prefix + ".") == true {
+			elements = append (elements, element)
+		}
+	}
+	sort.Strings (elements)
+	return elements
+}
+
+// Returns the roots of the system: the elements that no element depends on. The IDs are
+// sorted.
+func (someSystem *System) Roots () ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	roots := []string {}
+	for _, element := range someSystem.systemElements {
+		if len (someSystem.dependents [element]) == 0 {
+			roots = append (roots, element)
+		}
+	}
+	sort.Strings (roots)
+	return roots
+}
+
+// Returns the leaves of the system: the elements that have no dependency. These are the
+// elements that could be initialized first. The IDs are sorted.
+func (someSystem *System) Leaves () ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	leaves := []string {}
+	for _, element := range someSystem.systemElements {
+		if len (someSystem.dependencies [element]) == 0 {
+			leaves = append (leaves, element)
+		}
+	}
+	sort.Strings (leaves)
+	return leaves
+}
+
+// Returns the isolated elements of the system: the elements that have no dependency, and
+// that no element depends on. The IDs are sorted.
+func (someSystem *System) Isolated () ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	isolated := []string {}
+	for _, element := range someSystem.systemElements {
+		if len (someSystem.dependencies [element]) == 0 &&
+			len (someSystem.dependents [element]) == 0 {
+			isolated = append (isolated, element)
+		}
+	}
+	sort.Strings (isolated)
+	return isolated
+}
+
+// Returns the elements that could be initialized next, given the elements already
+// initialized: the elements not yet initialized, all of whose dependencies have been
+// initialized. This allows elements to be initialized step by step, as earlier elements
+// finish initializing.
+//
+// Inputs
+//
+// input 0: The IDs of the elements already initialized.
+//
+// Outpts
+//
+// outpt 0: The IDs of the elements ready to be initialized, in the order they were added
+// to the system.
+func (someSystem *System) ReadyAfter (initialized []string) ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	done := map[string]bool {}
+	for _, element := range initialized {
+		done [element] = true
+	}
+
+	ready := []string {}
+	for _, element := range someSystem.systemElements {
+		if done [element] == true {
+			continue
+		}
+		isReady := true
+		for _, dependency := range someSystem.orderingDependencies (element) {
+			if done [dependency] == false {
+				isReady = false
+				break
+			}
+		}
+		if isReady == true {
+			ready = append (ready, element)
+		}
+	}
+	return ready
+}
+
+// Creates an independent copy of the system. Modifying the copy would not affect the
+// original system, and vice versa.
+func (someSystem *System) Clone () (*System) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	clone := New ()
+	clone.options = someSystem.options
+	clone.systemElements = make ([]string, len (someSystem.systemElements))
+	copy (clone.systemElements, someSystem.systemElements)
+	for element, deps := range someSystem.dependencies {
+		depsCopy := make ([]string, len (deps))
+		copy (depsCopy, deps)
+		clone.dependencies [element] = depsCopy
+	}
+	for element := range someSystem.addedElements {
+		clone.addedElements [element] = struct{} {}
+	}
+	for dependency, dependents := range someSystem.dependents {
+		dependentsCopy := make ([]string, len (dependents))
+		copy (dependentsCopy, dependents)
+		clone.dependents [dependency] = dependentsCopy
+	}
+	for element, tag := range someSystem.tags {
+		clone.tags [element] = tag
+	}
+	for element, deps := range someSystem.optionalDependencies {
+		depsCopy := make ([]string, len (deps))
+		copy (depsCopy, deps)
+		clone.optionalDependencies [element] = depsCopy
+	}
+	for element, meta := range someSystem.metadata {
+		clone.metadata [element] = meta
+	}
+	for element, priority := range someSystem.priorities {
+		clone.priorities [element] = priority
+	}
+	return clone
+}
+
+// Describes the system, for debugging and logging. Each element is described on its own
+// line, with its dependencies, like the following: a -> [b c]. Lines are sorted by the ID
+// of the element.
+func (someSystem *System) String () (string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	elements := make ([]string, len (someSystem.systemElements))
+	copy (elements, someSystem.systemElements)
+	sort.Strings (elements)
+	lines := make ([]string, 0, len (elements))
+	for _, element := range elements {
+		lines = append (lines, fmt.Sprintf ("%s -> [%s]", element,
+			strings.Join (someSystem.dependencies [element], " ")))
+	}
+	return strings.Join (lines, "\n")
+}
+
+// This functions provides an order in which elements of the system could be safely
+// initialized.
+//
+// The order is deterministic: whenever more than one order is possible, ties are broken
+// using the order in which elements were added to the system, and the order in which the
+// dependencies of each element were given (unless the system has a comparator, or
+// elements with priorities, see WithReadyComparator () and AddElementPrio ()). Hence,
+// systems built using the same calls, in the same sequence, would always have the same
+// "init order". This operation only looks up the hash map of dependencies, and never
+// iterates it, so its random iteration order can not affect the result.
+//
+// Outpts
+// outpt 0: A string slice of the IDs of the elements in the system. The ascending order
+// of these IDs represents the "init order". If an error is encountered during the
+// operation, value of this data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors include: *MissingDependencyError and *CircleError, which
+// could be matched against ErrElementMissing and ErrCircleDetected, using errors.Is (),
+// and, when the system was created using WithMaxDepth () or WithOrderHook (),
+// ErrMaxDepthExceeded or ErrConcurrentModification.
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error. Possible values would look like the following:
+//
+// "Dependency 'x' is missing, needed through 'app -> api -> x'" - Value of outpt 2 when a
+// dependency of an element is not in the system. The chain shown is a shortest chain of
+// dependencies, leading from an element that no element depends on, to the missing
+// dependency, so the declaration at fault could be found easily. Whether a dependency
+// is missing depends only on whether it is in the system, never on the order in which
+// elements were added, nor on whether the dependency has already been placed in the
+// "init order".
+//
+// "Element 'r' is part of the circle 'r -> s -> t -> r'." - Value of outpt 2 when a cyclic
+// dependency is detected. All the elements forming the circle are listed, each depending
+// on the one after it. The circle always starts (and ends) with its smallest ID, and that
+// is the element named, so the same circle is always reported the same way, regardless
+// of the order in which elements were added.
+//
+// "Element 'r' has depth 5, which is greater than the limit 4." - Value of outpt 2 when a
+// chain of dependencies is longer than allowed by WithMaxDepth ().
+//
+// "The system was modified while its "init order" was being worked out" - Value of outpt
+// 2 when the system is modified while the hook given using WithOrderHook () runs.
+func (someSystem *System) InitOrder () ([]string, error, string) {
+	return someSystem.InitOrderContext (context.Background ())
+}
+
+// This function is just like InitOrder (), except that the operation could be cancelled
+// using a context. The context is checked regularly while the "init order" is being worked
+// out, and once it is done, the operation stops promptly.
+//
+// Inputs
+//
+// input 0: The context of the operation.
+//
+// Outpts
+//
+// outpt 0, outpt 1, and outpt 2: The same as those of InitOrder (). Additionally, if the
+// context is done before the operation completes, value of outpt 1 would be the error of
+// the context, and value of outpt 2 would look like the following: "Operation stopped:
+// context canceled".
+func (someSystem *System) InitOrderContext (ctx context.Context) ([]string, error,
+	string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+	return someSystem.initOrderContext (ctx, someSystem.orderHook ())
+}
+
+func (someSystem *System) orderHook () (func (string, int, int) (error, string)) { /*
+	This function returns a hook for initOrderContext (), that calls the order hook of
+	the system, and fails if the system is modified in the meantime. If the system has
+	no order hook, value nil is returned. It is meant to be used by operations that
+	already hold the lock of the system for reading. */
+	if someSystem.options.orderHook == nil {
+		return nil
+	}
+	modifications := someSystem.modifications
+	return func (element string, index, total int) (error, string) {
+		someSystem.callOrderHook (element, index, total)
+		if someSystem.modifications != modifications {
+			return ErrConcurrentModification, "The system was modified while its " +
+				"\"init order\" was being worked out"
+		}
+		return nil, ""
+	}
+}
+
+func (someSystem *System) callOrderHook (element string, index, total int) { /* This
+	function calls the order hook of the system, with the system unlocked. It is meant
+	to be used by operations that already hold the lock of the system for reading,
+	and the lock is held again when the function returns. */
+	someSystem.mutex.RUnlock ()
+	defer someSystem.mutex.RLock ()
+	someSystem.options.orderHook (element, index, total)
+}
+
+// This function is just like InitOrder (), except that the operation is abandoned if it
+// takes longer than some time. It is meant for code that has no context at hand; it
+// behaves just like InitOrderContext (), given a context with a timeout.
+//
+// Inputs
+//
+// input 0: The longest the operation may take.
+//
+// Outpts
+//
+// outpt 0, outpt 1, and outpt 2: The same as those of InitOrder (). Additionally, if the
+// operation takes longer than allowed, value of outpt 1 would be ErrTimeout, and value
+// of outpt 2 would look like the following: "Operation stopped: it took longer than 1s".
+func (someSystem *System) InitOrderTimeout (d time.Duration) ([]string, error, string) {
+
+	ctx, cancel := context.WithTimeout (context.Background (), d)
+	defer cancel ()
+
+	initOrder, errX, errDescp := someSystem.InitOrderContext (ctx)
+	if errors.Is (errX, context.DeadlineExceeded) == true {
+		return nil, ErrTimeout, fmt.Sprintf ("Operation stopped: it took longer " +
+			"than %s", d)
+	}
+	return initOrder, errX, errDescp
+}
+
+func (someSystem *System) initOrder () ([]string, error, string) { /* This function is the
+	lock-free version of InitOrder (), and is meant to be used by operations that
+	already hold the lock of the system. */
+	return someSystem.initOrderContext (context.Background (), nil)
+}
+
+func (someSystem *System) initOrderContext (ctx context.Context, hook func (string, int,
+	int) (error, string)) ([]string, error, string) { /* This function is the lock-free
+	version of InitOrderContext (), and is meant to be used by operations that already
+	hold the lock of the system. The hook, if not nil, is called as each element is
+	placed in the "init order"; if it returns an error, the operation stops with that
+	error. */
+
+	// Declaration of some data to be used for this operation. { ...
+	initOrder := []string {}
+	ordered := map[string]bool {} // The elements already in the "init order".
+	depthOf := map[string]int {} // Used only when the depth of elements is limited.
+	preferred := someSystem.hasPreferences ()
+	// ... }
+
+	/* The elements of this system are taken one-by-one, in the order they were added,
+		and added in an appropriate place, in the "init order" that is being
+		generated. */
+	for _, element := range someSystem.systemElements {
+		if ordered [element] == true {
+			continue
+		}
+
+		var errX error = nil
+		var errDescp string
+		alreadyChecked := len (initOrder)
+		initOrder, errX, errDescp = addToInitOrder (ctx, initOrder, ordered, element,
+			someSystem)
+		if errX != nil {
+			return nil, errX, errDescp
+		}
+		for _, orderedElement := range initOrder [alreadyChecked:] {
+			errY, errDescp := someSystem.checkDepth (orderedElement, depthOf)
+			if errY != nil {
+				return nil, errY, errDescp
+			}
+		}
+		if hook != nil && preferred == false {
+			for index := alreadyChecked; index < len (initOrder); index ++ {
+				errY, errDescp := hook (initOrder [index], index,
+					len (someSystem.systemElements))
+				if errY != nil {
+					return nil, errY, errDescp
+				}
+			}
+		}
+	}
+
+	/* When some elements have priorities, or a comparator decides between ready
+		elements, the "init order" worked out so far only shows that the system has
+		a valid "init order". The elements are then ordered again, taking those
+		preferences into account. */
+	if preferred == true {
+		var errX error
+		var errDescp string
+		initOrder, errX, errDescp = someSystem.orderByPreference (ctx,
+			someSystem.systemElements)
+		if errX != nil {
+			return nil, errX, errDescp
+		}
+		if hook != nil {
+			for index, element := range initOrder {
+				errY, errDescp := hook (element, index, len (initOrder))
+				if errY != nil {
+					return nil, errY, errDescp
+				}
+			}
+		}
+	}
+
+	return initOrder, nil, ""
+}
+
+func (someSystem *System) checkDepth (element string, depthOf map[string]int) (error,
+	string) { /* This function works out the depth of an element newly added to the
+	"init order", from the depths of its dependencies, and records it. If the depth is
+	greater than the limit of the system, an error is returned. Nothing is done if the
+	system has no limit. It is meant to be used by operations that already hold the
+	lock of the system. */
+
+	if someSystem.options.maxDepth <= 0 {
+		return nil, ""
+	}
+	depth := 0
+	for _, dependency := range someSystem.orderingDependencies (element) {
+		if depthOf [dependency] + 1 > depth {
+			depth = depthOf [dependency] + 1
+		}
+	}
+	depthOf [element] = depth
+	if depth > someSystem.options.maxDepth {
+		return fmt.Errorf ("%w: element '%s' has depth %d", ErrMaxDepthExceeded,
+			element, depth), fmt.Sprintf ("Element '%s' has depth %d, which is " +
+			"greater than the limit %d.", element, depth,
+			someSystem.options.maxDepth)
+	}
+	return nil, ""
+}
+
+// Checks if an "init order" could be worked out for the system, that is, if no
+// dependency is missing and no cyclic dependency exists. Only the first problem found is
+// reported. The elements are checked just like in InitOrder (), but the "init order"
+// itself is never built, and the hook of the system, if any, is not called.
+//
+// Outpts
+// outpt 0: If the system is well-formed, value would be nil. Otherwise, value would be
+// the error that InitOrder () would return.
+//
+// outpt 1: When the value of outpt 0 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) Validate () (error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	return someSystem.validate (true)
+}
+
+func (someSystem *System) validate (limitDepth bool) (error, string) { /* This function
+	is the lock-free version of Validate (), and is meant to be used by operations that
+	already hold the lock of the system. The elements are checked in the same order as
+	in initOrderContext (), so the same problem is reported first; only the elements
+	placed for the element being checked are kept, and they are dropped once they are
+	checked. If input 0 is false, the depth limit of the system is not accounted for. */
+
+	// Declaration of some data to be used for this operation. { ...
+	placed := []string {} // The elements placed for the element being checked.
+	ordered := map[string]bool {} // The elements already placed.
+	depthOf := map[string]int {} // Used only when the depth of elements is limited.
+	// ... }
+
+	for _, element := range someSystem.systemElements {
+		if ordered [element] == true {
+			continue
+		}
+
+		var errX error = nil
+		var errDescp string
+		placed, errX, errDescp = addToInitOrder (context.Background (), placed [:0],
+			ordered, element, someSystem)
+		if errX != nil {
+			return errX, errDescp
+		}
+		if limitDepth == false {
+			continue
+		}
+		for _, placedElement := range placed {
+			errY, errDescp := someSystem.checkDepth (placedElement, depthOf)
+			if errY != nil {
+				return errY, errDescp
+			}
+		}
+	}
+	return nil, ""
 }
 
-type System struct {
-	systemElements []string // All elements in the system.
-	dependencies map[string][]string /* The dependencies of individual elements in the
-		system. The list of dependencies of each individual element, would be
-		stored in this hash map, where the key of each record would be the ID of
-		the element. */
-	addedElements string /* A string that keeps track of what elements have been added
-		to the system. It is just a redundant data meant to help speed up some
-		certain operations of this data type. */
+// Tells if the system is a directed acyclic graph, that is, if no cyclic dependency
+// exists and no dependency is missing. Unlike Validate (), the depth limit of the system
+// (see WithMaxDepth ()) is not accounted for, as chains of dependencies, however long,
+// do not make the system cyclic.
+func (someSystem *System) IsAcyclic () (bool) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	errX, _ := someSystem.validate (false)
+	return errX == nil
 }
 
-// Adds an element to the system.
+// Checks if an order, obtained from elsewhere, is a valid "init order" of the system: it
+// must contain every element of the system, each exactly once, and nothing else, and
+// every element must come after all its dependencies (including its optional
+// dependencies in the system).
 //
 // Inputs
 //
-// input 0: The new element to be added to the system. Value can not be an empty string.
-//
-// input 1: The IDs of the dependencies of the element. The ID of a dependency may not be
-// an empty string.
+// input 0: The IDs of the elements, in the order to be checked.
 //
 // Outpts
 //
-// outpt 0: Possible errors include: ErrAlreadyAdded.
-func (someSystem *System) AddElement (newElement string, dependencies []string) (error) {
+// outpt 0: Value would be true, if the order is a valid "init order" of the system.
+//
+// outpt 1: When the value of outpt 0 is false, value of this data would describe the first
+// problem found. Possible values would look like the following:
+//
+// "'x' is not an element of the system"
+//
+// "Element 'x' appears more than once"
+//
+// "Element 'x' comes before its dependency 'y'"
+//
+// "Dependency 'y' of element 'x' is missing"
+//
+// "Element 'x' is not in the order"
+func (someSystem *System) IsValidOrder (order []string) (bool, string) {
 
-	if newElement == "" {
-		return errors.New ("Empty string can not be used as ID of an element.")
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	placed := map[string]bool {}
+	for _, element := range order {
+		if someSystem.hasElement (element) == false {
+			return false, fmt.Sprintf ("'%s' is not an element of the system", element)
+		}
+		if placed [element] == true {
+			return false, fmt.Sprintf ("Element '%s' appears more than once", element)
+		}
+		for _, dep := range someSystem.orderingDependencies (element) {
+			if placed [dep] == true {
+				continue
+			}
+			if someSystem.hasElement (dep) == false {
+				return false, fmt.Sprintf ("Dependency '%s' of element '%s' is " +
+					"missing", dep, element)
+			}
+			return false, fmt.Sprintf ("Element '%s' comes before its dependency " +
+				"'%s'", element, dep)
+		}
+		placed [element] = true
 	}
-	for _, dep := range dependencies {
-		if dep == "" {
-			return errors.New ("The ID of a dependency is an empty string.")
+	for _, element := range someSystem.systemElements {
+		if placed [element] == false {
+			return false, fmt.Sprintf ("Element '%s' is not in the order", element)
 		}
 	}
-	if strings.Contains (someSystem.addedElements, newElement + "/") == true {
-		return ErrAlreadyAdded
+	return true, ""
+}
+
+// Returns every dependency that some element of the system has, but which is not in the
+// system. Unlike Validate (), all such dependencies are reported at once. The IDs are
+// sorted, and each appears once.
+func (someSystem *System) MissingDependencies () ([]string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	missing := []string {}
+	for _, element := range someSystem.systemElements {
+		for _, dependency := range someSystem.dependencies [element] {
+			if someSystem.hasElement (dependency) == false &&
+				slices.IsElementInStringSlice (missing, dependency) == false {
+				missing = append (missing, dependency)
+			}
+		}
 	}
-	someSystem.systemElements = append (someSystem.systemElements, newElement)
-	someSystem.dependencies [newElement] = dependencies
-	someSystem.addedElements += newElement + "/"
-	return nil
+	sort.Strings (missing)
+	return missing
 }
 
-// This functions provides an order in which elements of the system could be safely
-// initialized.
+// This function provides an order in which elements of the system could be safely shut
+// down: an element would always come before its dependencies. The order is the exact
+// reverse of the order provided by InitOrder ().
 //
 // Outpts
 // outpt 0: A string slice of the IDs of the elements in the system. The ascending order
-// of these IDs represents the "init order". If an error is encountered during the
+// of these IDs represents the "shutdown order". If an error is encountered during the
 // operation, value of this data would be nil.
 //
 // outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
-// that occured.
+// that occured. Possible errors are the same as those of InitOrder ().
 //
 // outpt 2: When the value of outpt 1 is an error, value of this data would be a more
-// precise description of the error. Possible values would look like the following:
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) ShutdownOrder () ([]string, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	initOrder, errX, errDescp := someSystem.initOrder ()
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+
+	shutdownOrder := make ([]string, len (initOrder))
+	for index, element := range initOrder {
+		shutdownOrder [len (initOrder) - 1 - index] = element
+	}
+	return shutdownOrder, nil, ""
+}
+
+// This function groups the elements of the system into levels that could be initialized
+// one after the other. The elements of a level depend only on elements of earlier levels,
+// hence the elements of a level could be initialized concurrently, once all the earlier
+// levels have been initialized. The first level contains the elements with no dependency.
 //
-// "Dependency 'x' is missing" - Value of outpt 2 when a dependency of an element is not
-// in the system.
+// Outpts
+// outpt 0: The levels, in the order they should be initialized. Within a level, elements
+// appear in the order they have in the "init order". If an error is encountered during
+// the operation, value of this data would be nil.
 //
-// "Element 'r' is part of the circle"- Value of outpt 2 when a cyclic dependency is
-// detected.
-func (someSystem *System) InitOrder () ([]string, error, string) {
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of InitOrder ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) InitLevels () ([][]string, error, string) {
 
-	// Declaration of some data to be used for this operation. { ...
-	elements := someSystem.systemElements
-	initOrder := []string {}
-	waitingList := []string {}
-	// ... }
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
 
-	/* The elements of this system are popped one-by-one, and added in an appropriate
-		place, in the "init order" that is being generated. */
-	for {
-		if len (elements) == 0 {
-			break
+	initOrder, levelOf, errX, errDescp := someSystem.depths ()
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+
+	levels := [][]string {}
+	for _, element := range initOrder {
+		level := levelOf [element]
+		for level >= len (levels) {
+			levels = append (levels, []string {})
 		}
+		levels [level] = append (levels [level], element)
+	}
+	return levels, nil, ""
+}
 
-		elementUnderProcessing := elements [0]
-		var errX error = nil
-		var errDescp string
-		initOrder, elements, errX, errDescp = addToInitOrder (initOrder,
-			elementUnderProcessing, waitingList, elements, someSystem)
+// Works out the most elements that could be initialized at the same time, when the system
+// is initialized level by level (see InitLevels ()): the number of elements of its
+// largest level. This is, for example, the most workers RunParallel () could keep busy.
+//
+// Outpts
+// outpt 0: The number of elements of the largest level. If the system has no element, or
+// an error is encountered during the operation, value would be 0.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of InitOrder ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) MaxParallelism () (int, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	_, levelOf, errX, errDescp := someSystem.depths ()
+	if errX != nil {
+		return 0, errX, errDescp
+	}
+
+	width := map[int]int {} // The number of elements of each level.
+	widest := 0
+	for _, level := range levelOf {
+		width [level] ++
+		if width [level] > widest {
+			widest = width [level]
+		}
+	}
+	return widest, nil, ""
+}
+
+// Works out how deep each element of the system sits in the dependency graph. An element
+// with no dependency has depth 0, while the depth of any other element is one more than
+// the greatest depth of its dependencies. The depth of an element is also the index of
+// its level, in the levels provided by InitLevels ().
+//
+// Outpts
+// outpt 0: A hash map of the depth of every element, where the key of each record is the
+// ID of the element. If an error is encountered during the operation, value of this data
+// would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of InitOrder ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) Depths () (map[string]int, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	_, depthOf, errX, errDescp := someSystem.depths ()
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+	return depthOf, nil, ""
+}
+
+// Works out the level of each element of the system: the index of the level the element
+// is in, among the levels provided by InitLevels (). An element with no dependency is at
+// level 0, while any other element is one level above the highest of its dependencies.
+// Levels are the same as depths, hence this function is just like Depths ().
+//
+// Outpts
+// outpt 0, outpt 1, and outpt 2: The same as those of Depths ().
+func (someSystem *System) LevelOf () (map[string]int, error, string) {
+	return someSystem.Depths ()
+}
+
+func (someSystem *System) depths () ([]string, map[string]int, error, string) { /* This
+	function works out the "init order" of the system, and the depth of every element.
+	It is meant to be used by operations that already hold the lock of the system. */
+
+	initOrder, errX, errDescp := someSystem.initOrder ()
+	if errX != nil {
+		return nil, nil, errX, errDescp
+	}
+
+	/* Since an element always comes after its dependencies in the "init order", the
+		depth of every dependency is known before the depth of the element is
+		worked out. */
+	depthOf := map[string]int {}
+	for _, element := range initOrder {
+		depth := 0
+		for _, dependency := range someSystem.orderingDependencies (element) {
+			if depthOf [dependency] + 1 > depth {
+				depth = depthOf [dependency] + 1
+			}
+		}
+		depthOf [element] = depth
+	}
+	return initOrder, depthOf, nil, ""
+}
+
+// Returns every element an element depends on, directly or indirectly. Optional
+// dependencies in the system are included, as they affect the "init order". Just like
+// with InitOrderFor (), the result is remembered until the system is next modified.
+//
+// Inputs
+//
+// input 0: The ID of the element whose dependencies are needed.
+//
+// Outpts
+//
+// outpt 0: The IDs of all the direct and indirect dependencies of the element, each
+// appearing once. They are given in an order in which they could be safely initialized.
+// If an error is encountered during the operation, value of this data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors include: ErrElementNotFound, and the errors of
+// InitOrder ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) TransitiveDependencies (element string) ([]string, error,
+	string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if someSystem.hasElement (element) == false {
+		return nil, ErrElementNotFound, fmt.Sprintf ("Element '%s' is not in the system",
+			element)
+	}
+
+	initOrder, errX, errDescp := someSystem.orderFor (element)
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+
+	// The element itself is always the last in its own "init order".
+	deps := make ([]string, len (initOrder) - 1)
+	copy (deps, initOrder)
+	return deps, nil, ""
+}
+
+// This function provides an order in which an element, and only the elements it depends
+// on, directly or indirectly, could be safely initialized. It is the "init order" of the
+// element alone, for when only that element should be brought up. Cyclic dependencies and
+// missing dependencies are only looked for among those elements. The elements come in
+// the same order as in InitOrder (), priorities and the comparator of the system
+// included.
+//
+// The result for each element is remembered until the system is next modified, so asking
+// about the same element again, or calling TransitiveDependencies () for it, is cheap.
+//
+// Inputs
+//
+// input 0: The ID of the element.
+//
+// Outpts
+//
+// outpt 0: The IDs of the element and all its direct and indirect dependencies, each
+// appearing once. The ascending order of these IDs represents their "init order", hence
+// the element itself is always the last. If an error is encountered during the
+// operation, value of this data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of TransitiveDependencies ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) InitOrderFor (element string) ([]string, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if someSystem.hasElement (element) == false {
+		return nil, ErrElementNotFound, fmt.Sprintf ("Element '%s' is not in the system",
+			element)
+	}
+
+	initOrder, errX, errDescp := someSystem.orderFor (element)
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+	initOrderCopy := make ([]string, len (initOrder))
+	copy (initOrderCopy, initOrder)
+	return initOrderCopy, nil, ""
+}
+
+// Extracts the part of the system needed by an element: a new system containing the
+// element, all its direct and indirect dependencies, and the dependencies among them.
+// Elements keep the order in which they were added to the original system.
+//
+// Inputs
+//
+// input 0: The ID of the element.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error is encountered during the operation, value of this
+// data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of TransitiveDependencies ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder ().
+func (someSystem *System) Subsystem (element string) (*System, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	return someSystem.prune ([]string {element})
+}
+
+// Extracts the part of the system needed by some elements, for example, the entry points
+// of a particular deployment: a new system containing the elements, all their direct and
+// indirect dependencies, and the dependencies among them. Every other element is left
+// out. Elements keep the order in which they were added to the original system.
+//
+// Inputs
+//
+// input 0: The IDs of the elements.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error is encountered during the operation, value of this
+// data would be nil.
+//
+// outpt 1: If operation succeeds, value would be nil. Otherwise, value would be the error
+// that occured. Possible errors are the same as those of TransitiveDependencies ().
+//
+// outpt 2: When the value of outpt 1 is an error, value of this data would be a more
+// precise description of the error, just like in InitOrder (). When one of the elements
+// is not in the system, value would look like the following: "Element 'x' is not in the
+// system".
+func (someSystem *System) Prune (roots []string) (*System, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	return someSystem.prune (roots)
+}
+
+func (someSystem *System) prune (roots []string) (*System, error, string) { /* This
+	function is the lock-free version of Prune (), and is meant to be used by
+	operations that already hold the lock of the system. */
+
+	included := map[string]bool {}
+	for _, root := range roots {
+		if someSystem.hasElement (root) == false {
+			return nil, ErrElementNotFound, fmt.Sprintf ("Element '%s' is not in " +
+				"the system", root)
+		}
+		if included [root] == true {
+			continue
+		}
+		_, errX, errDescp := addToInitOrder (context.Background (), []string {},
+			included, root, someSystem)
 		if errX != nil {
 			return nil, errX, errDescp
 		}
 	}
+	return someSystem.subsystem (included), nil, ""
+}
+
+func (someSystem *System) subsystem (included map[string]bool) (*System) { /* This
+	function creates a new system, made up of some elements of the system, and their
+	dependencies. The dependencies of the elements are expected to also be included.
+	It is meant to be used by operations that already hold the lock of the system. */
+	newSystem := New ()
+	for _, element := range someSystem.systemElements {
+		if included [element] == false {
+			continue
+		}
+		newSystem.copyElement (someSystem, element)
+	}
+	return newSystem
+}
+
+func (someSystem *System) copyElement (other *System, element string) { /* This function
+	adds an element of another system to the system, with its dependencies, and
+	everything else known about it. It is meant to be used by operations that already
+	hold the lock of the system for writing, and the lock of the other system. */
+	deps := make ([]string, len (other.dependencies [element]))
+	copy (deps, other.dependencies [element])
+	someSystem.systemElements = append (someSystem.systemElements, element)
+	someSystem.addedElements [element] = struct{} {}
+	someSystem.setDependencies (element, deps)
+	if tag, okX := other.tags [element]; okX == true {
+		someSystem.tags [element] = tag
+	}
+	if optionalDeps, okX := other.optionalDependencies [element]; okX == true {
+		optionalDepsCopy := make ([]string, len (optionalDeps))
+		copy (optionalDepsCopy, optionalDeps)
+		someSystem.optionalDependencies [element] = optionalDepsCopy
+	}
+	if meta, okX := other.metadata [element]; okX == true {
+		someSystem.metadata [element] = meta
+	}
+	if priority, okX := other.priorities [element]; okX == true {
+		someSystem.priorities [element] = priority
+	}
+}
+
+func (someSystem *System) orderByReadiness (ctx context.Context, elements []string,
+	push func (element string), pop func () (string)) ([]string, error, string) { /*
+	This function works out an "init order" of some elements of the system, in which,
+	whenever more than one element is ready (that is, all its dependencies are already
+	in the "init order"), the element that comes next is decided by the caller: each
+	element is given to function "push" as soon as it is ready, and function "pop"
+	must remove and return the ready element that should come next. Elements are given
+	to "push" in the order they appear in input 1, whenever more than one becomes ready
+	at the same time.
+
+	The elements must include every dependency of each of them, and must have been
+	checked to have a valid "init order", before this function is used. If the context
+	is done, the operation stops, and the error of the context is returned. It is meant
+	to be used by operations that already hold the lock of the system. */
+
+	// Declaration of some data to be used for this operation. { ...
+	included := make (map[string]bool, len (elements)) // The elements to be ordered.
+	pendingDeps := make (map[string]int, len (elements)) /* The number of dependencies
+		of each element, not yet in the "init order". */
+	dependents := map[string][]string {} /* The elements that must come after each
+		element, in the order of input 1. Unlike the index of dependents of the
+		system, optional dependencies are accounted for. */
+	initOrder := make ([]string, 0, len (elements))
+	// ... }
+
+	for _, element := range elements {
+		included [element] = true
+	}
+	for _, element := range elements {
+		for _, dep := range someSystem.orderingDependencies (element) {
+			if included [dep] == true {
+				pendingDeps [element] ++
+				dependents [dep] = append (dependents [dep], element)
+			}
+		}
+		if pendingDeps [element] == 0 {
+			push (element)
+		}
+	}
 
+	for len (initOrder) < len (elements) {
+		if errX := ctx.Err (); errX != nil {
+			return nil, errX, "Operation stopped: " + errX.Error ()
+		}
+		element := pop ()
+		initOrder = append (initOrder, element)
+
+		// Dependents of the element, whose dependencies are now all in the order.
+		for _, dependent := range dependents [element] {
+			pendingDeps [dependent] --
+			if pendingDeps [dependent] == 0 {
+				push (dependent)
+			}
+		}
+	}
 	return initOrder, nil, ""
 }
 
-func addToInitOrder (initOrder []string, element string, waitingList []string,
-		elements []string, someSystem *System) ([]string, []string, error,
-		string) { /* This function is not meant to be used outside this package.
-		The function simply takes an init order and an element, then adds the
-		element to a safe place in the "init order".
+func (someSystem *System) hasPreferences () (bool) { /* This function tells if the "init
+	order" of the system depends on preferences, that is, if some elements have
+	priorities, or the system has a comparator for ready elements. It is meant to be
+	used by operations that already hold the lock of the system. */
+	return len (someSystem.priorities) > 0 || someSystem.options.readyLess != nil
+}
+
+func (someSystem *System) orderByPreference (ctx context.Context, elements []string) (
+	[]string, error, string) { /* This function works out an "init order" of some
+	elements of the system, just like orderByReadiness (), in which, whenever more than
+	one element is ready, the most preferred one comes next: the one with the highest
+	priority, and among those, the one the comparator of the system puts first, or
+	when the system has no comparator, the one with the smallest ID. When the
+	comparator puts neither first, the one that appears first in input 1 comes next. It
+	is meant to be used by operations that already hold the lock of the system. */
+
+	position := make (map[string]int, len (elements))
+	for index, element := range elements {
+		position [element] = index
+	}
+	ready := &elementHeap {less: func (a, b string) (bool) {
+		aPriority, bPriority := someSystem.priorities [a], someSystem.priorities [b]
+		switch {
+		case aPriority != bPriority:
+			return aPriority > bPriority
+		case someSystem.options.readyLess != nil:
+			if someSystem.options.readyLess (a, b) == true {
+				return true
+			}
+			if someSystem.options.readyLess (b, a) == true {
+				return false
+			}
+			return position [a] < position [b]
+		}
+		return a < b
+	}}
+	return someSystem.orderByReadiness (ctx, elements,
+		func (element string) { heap.Push (ready, element) },
+		func () (string) { return heap.Pop (ready).(string) })
+}
+
+type elementHeap struct { /* A heap of elements, for use with container/heap, in which
+	the first element is the one function "less" puts before every other. */
+	elements []string
+	less func (a, b string) (bool)
+}
+
+func (someHeap *elementHeap) Len () (int) { return len (someHeap.elements) }
+
+func (someHeap *elementHeap) Less (i, j int) (bool) {
+	return someHeap.less (someHeap.elements [i], someHeap.elements [j])
+}
+
+func (someHeap *elementHeap) Swap (i, j int) {
+	someHeap.elements [i], someHeap.elements [j] = someHeap.elements [j],
+		someHeap.elements [i]
+}
+
+func (someHeap *elementHeap) Push (element any) {
+	someHeap.elements = append (someHeap.elements, element.(string))
+}
+
+func (someHeap *elementHeap) Pop () (any) {
+	last := someHeap.elements [len (someHeap.elements) - 1]
+	someHeap.elements = someHeap.elements [: len (someHeap.elements) - 1]
+	return last
+}
+
+func addToInitOrder (ctx context.Context, initOrder []string, ordered map[string]bool,
+		element string, someSystem *System) ([]string, error, string) { /* This
+		function is not meant to be used outside this package. The function
+		simply takes an init order and an element, then adds the element, and
+		all its dependencies not yet in the init order, to a safe place in the
+		"init order".
+
+		The work is done using a stack held in memory, rather than recursion, so
+		very long chains of dependencies can not exhaust the stack of the
+		goroutine.
 
 	Inputs
-	input 0: The init order where the element should be added.
-	input 1: The element to be added.
-	input 2: You may need to read the code to fully grasp the essence of this data.
-		This data is a stack. When an element needs to be added to the init order,
-		but has dependencies, the element is placed in this waiting list, and we
-		try to add the dependencies to the init order first. Once the dependencies
-		have been added to the init order, the element can then be popped from
-		this stack and added to the init order.
-	input 3: The system whose's init order is being worked on.
+	input 0: The context of the operation. If it is done, the operation stops.
+	input 1: The init order where the element should be added.
+	input 2: The elements already in the init order. Elements added to the init order
+		are also added to this set.
+	input 3: The element to be added.
+	input 4: The system whose's init order is being worked on.
 
 	Outpts
 	outpt 0: A modified version of the init order. If this operation fails, the value
@@ -126,63 +2090,203 @@ func addToInitOrder (initOrder []string, element string, waitingList []string,
 		If this operation should fail, value of this data would be a more precise
 		description of the error. */
 
-	// Checking for existence of a circle.
-	if slices.IsElementInStringSlice (waitingList, element) == true {
-		return nil, nil, ErrCircleDetected, "Element '" + element +
-			"' is part of the circle."
+	if errX := ctx.Err (); errX != nil {
+		return nil, errX, "Operation stopped: " + errX.Error ()
 	}
 
-	// If the element has no dependency, it is added to the init order, straightaway.
-	if len (someSystem.dependencies [element]) == 0 {
-		elements = slices.RemoveFromStringSlice (elements, element)
-		initOrder := append (initOrder, element)
-		return initOrder, elements, nil, ""
+	/* You may need to read the code to fully grasp the essence of this data. This data
+		is a stack, serving as a waiting list. When an element needs to be added to
+		the init order, but has dependencies, the element is placed in this waiting
+		list, and we try to add the dependencies to the init order first. Once the
+		dependencies have been added to the init order, the element can then be
+		popped from this stack and added to the init order. Each entry also keeps
+		track of how many dependencies of its element have been worked on. */
+	type waitingElement struct {
+		element string
+		nextDependency int
 	}
+	waitingList := []waitingElement {{element, 0}}
+	waitingIndex := map[string]int {element: 0} /* The position of each element in the
+		waiting list. */
+
+	for len (waitingList) > 0 {
+		top := &waitingList [len (waitingList) - 1]
+		deps := someSystem.orderingDependencies (top.element)
 
-	// If the element has any dependency, the dependencies are added first. { ...
-	waitingList = append (waitingList, element) /* Placing the element in the waiting
-		list. Once all its dependencies have been added to the init order, it
- 		would be removed from this waiting list. */
+		/* At this stage all dependencies of the element must have been added to the
+			init order. Now, the element will be removed from the waiting list,
+			and added to the init order. */
+		if top.nextDependency == len (deps) {
+			delete (waitingIndex, top.element)
+			ordered [top.element] = true
+			initOrder = append (initOrder, top.element)
+			waitingList = waitingList [: len (waitingList) - 1]
+			continue
+		}
+
+		dependency := deps [top.nextDependency]
+		top.nextDependency ++
 
-	// Adding dependencies to the "init order".
-	for _, dependency := range someSystem.dependencies [element] {
 		/* If the dependency is already in the "init order", there is no need
 			reading it. */
-		if slices.IsElementInStringSlice (initOrder, dependency) == true {
+		if ordered [dependency] == true {
 			continue
 		}
 
 		// If dependency is not in the system, error is returned.
-		if slices.IndexInStringSlice (elements, dependency) == -1 {
-			return nil, nil, ErrElementMissing, fmt.Sprintf (
-				"Dependency '%s' is missing", dependency)
+		if someSystem.hasElement (dependency) == false {
+			chain := someSystem.chainTo (top.element, dependency)
+			return nil, &MissingDependencyError {top.element, dependency, chain},
+				fmt.Sprintf ("Dependency '%s' is missing, needed through '%s'",
+				dependency, strings.Join (chain, " -> "))
 		}
 
-		// Adding dependency to the "init order". { ...
-		var errZ error = nil
-		var errDescp string
-		initOrder, elements, errZ, errDescp = addToInitOrder (initOrder,
-			dependency, waitingList, elements, someSystem)
-		// ... }
+		if errX := ctx.Err (); errX != nil {
+			return nil, errX, "Operation stopped: " + errX.Error ()
+		}
 
-		if errZ != nil {
-			return nil, nil, errZ, errDescp
+		/* Checking for existence of a circle. The elements from the position of the
+			dependency in the waiting list, up to the top of the waiting list, are
+			the elements forming the circle. */
+		if index, okX := waitingIndex [dependency]; okX == true {
+			members := []string {}
+			for _, waiting := range waitingList [index:] {
+				members = append (members, waiting.element)
+			}
+			circle := canonicalCircle (members)
+			return nil, &CircleError {circle}, "Element '" + circle [0] +
+				"' is part of the circle '" + strings.Join (circle, " -> ") + "'."
 		}
+
+		// Placing the dependency in the waiting list, so its dependencies are added first.
+		waitingIndex [dependency] = len (waitingList)
+		waitingList = append (waitingList, waitingElement {dependency, 0})
 	}
 
-	/* At this stage all dependencies of the element must have been added to the init
-		order. Now, the element will be removed from the waiting list, and added
-		to the init order. */
-	waitingList = slices.RemoveFromStringSlice (waitingList, element)
-	elements = slices.RemoveFromStringSlice (elements, element)
-	initOrder = append (initOrder, element)
-	// ... }
+	return initOrder, nil, ""
+}
+
+func (someSystem *System) chainTo (element, dependency string) ([]string) { /* This
+	function finds a shortest chain of dependencies leading from an element that no
+	element depends on, to a dependency of an element, using a breadth-first search
+	that goes from the element to its dependents. The chain ends with the element and
+	the dependency. If every element depending on the element, directly or
+	indirectly, is itself depended on, the chain starts with the element. It is meant
+	to be used by operations that already hold the lock of the system. */
+
+	next := map[string]string {element: ""} /* The element after each element, in the
+		chain, that is, the element through which each element was reached. */
+	queue := []string {element}
+	top := element
+	for len (queue) > 0 {
+		current := queue [0]
+		queue = queue [1:]
+		if len (someSystem.dependents [current]) == 0 {
+			top = current
+			break
+		}
+		for _, dependent := range someSystem.dependents [current] {
+			if _, okX := next [dependent]; okX == true {
+				continue
+			}
+			next [dependent] = current
+			queue = append (queue, dependent)
+		}
+	}
+
+	chain := []string {}
+	for current := top; current != ""; current = next [current] {
+		chain = append (chain, current)
+	}
+	return append (chain, dependency)
+}
 
-	return initOrder, elements, nil, ""
+func canonicalCircle (members []string) ([]string) { /* This function takes the elements
+	forming a circle, each depending on the one after it (and the last depending on
+	the first), and returns the circle starting with the smallest ID, and closed by
+	repeating it at the end. This way, a circle is always reported the same way,
+	regardless of the element from which it was found. */
+
+	start := 0
+	for index, member := range members {
+		if member < members [start] {
+			start = index
+		}
+	}
+	circle := make ([]string, 0, len (members) + 1)
+	circle = append (circle, members [start:]...)
+	circle = append (circle, members [:start]...)
+	return append (circle, members [start])
 }
 
 var (
 	ErrAlreadyAdded error = errors.New ("The element has already been added")
 	ErrCircleDetected error = errors.New ("A circle has been detected")
 	ErrElementMissing error = errors.New ("An element is missing")
+	ErrElementNotFound error = errors.New ("The element is not in the system")
+	ErrConflict error = errors.New ("The element is defined differently in both systems")
+	ErrSelfDependency error = errors.New ("An element can not depend on itself")
+	ErrConcurrentModification error = errors.New ("The system was modified during the " +
+		"operation")
+	ErrTimeout error = errors.New ("The operation took longer than allowed")
+	ErrFrozen error = errors.New ("The system has been frozen, and can not be modified")
+	ErrMaxDepthExceeded error = errors.New ("A chain of dependencies is longer than " +
+		"allowed")
 )
+
+// The error returned when an element being added is already in the system. It matches
+// ErrAlreadyAdded, when checked using errors.Is ().
+type AlreadyAddedError struct {
+	Element string // The element being added.
+	Existing []string // The dependencies the element already has in the system.
+}
+
+func (someError *AlreadyAddedError) Error () (string) {
+	return fmt.Sprintf ("Element '%s' has already been added, with dependencies %v",
+		someError.Element, someError.Existing)
+}
+
+func (someError *AlreadyAddedError) Is (target error) (bool) {
+	return target == ErrAlreadyAdded
+}
+
+// The error returned when a dependency of an element is not in the system. It matches
+// ErrElementMissing, when checked using errors.Is ().
+type MissingDependencyError struct {
+	Element string // The element whose dependency is missing.
+	Dependency string // The missing dependency.
+	Chain []string /* A shortest chain of dependencies leading to the missing
+		dependency: it starts with an element that no element depends on, each
+		element depends on the one after it, and it ends with the element, and
+		the missing dependency. */
+}
+
+func (someError *MissingDependencyError) Error () (string) {
+	if len (someError.Chain) > 2 {
+		return fmt.Sprintf ("Dependency '%s' of element '%s' is missing (%s)",
+			someError.Dependency, someError.Element, strings.Join (someError.Chain,
+			" -> "))
+	}
+	return fmt.Sprintf ("Dependency '%s' of element '%s' is missing", someError.Dependency,
+		someError.Element)
+}
+
+func (someError *MissingDependencyError) Is (target error) (bool) {
+	return target == ErrElementMissing
+}
+
+// The error returned when a cyclic dependency is detected. It matches ErrCircleDetected,
+// when checked using errors.Is ().
+type CircleError struct {
+	Cycle []string /* The elements forming the circle, each depending on the one after
+		it, starting with the smallest ID. The first element is repeated at the
+		end, to close the circle. */
+}
+
+func (someError *CircleError) Error () (string) {
+	return "A circle has been detected: " + strings.Join (someError.Cycle, " -> ")
+}
+
+func (someError *CircleError) Is (target error) (bool) {
+	return target == ErrCircleDetected
+}
@@ -0,0 +1,504 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInitOrderDeepChain (t *testing.T) {
+
+	const depth = 100000
+
+	someSystem := New ()
+	for index := 0; index < depth; index ++ {
+		dependencies := []string {}
+		if index > 0 {
+			dependencies = append (dependencies, fmt.Sprint (index - 1))
+		}
+		if errX := someSystem.AddElement (fmt.Sprint (index), dependencies); errX != nil {
+			t.Fatalf ("Element %d could not be added: %v", index, errX)
+		}
+	}
+
+	initOrder, errY, errDescp := someSystem.InitOrder ()
+	if errY != nil {
+		t.Fatalf ("The \"init order\" could not be worked out: %v: %s", errY, errDescp)
+	}
+	if len (initOrder) != depth {
+		t.Fatalf ("The \"init order\" has %d elements, rather than %d.",
+			len (initOrder), depth)
+	}
+	for index, element := range initOrder {
+		if element != fmt.Sprint (index) {
+			t.Fatalf ("Element %s is at index %d of the \"init order\".", element,
+				index)
+		}
+	}
+}
+
+func TestInitOrderDeterministic (t *testing.T) {
+
+	build := func () (*System) {
+		someSystem := New ()
+		someSystem.AddElement ("web", []string {"db", "cache", "queue"})
+		someSystem.AddElement ("worker", []string {"queue", "db"})
+		someSystem.AddElement ("db", []string {"config"})
+		someSystem.AddElement ("cache", []string {"config"})
+		someSystem.AddElement ("queue", []string {})
+		someSystem.AddElement ("config", []string {})
+		someSystem.AddElement ("metrics", []string {})
+		return someSystem
+	}
+
+	firstOrder, errX, _ := build ().InitOrder ()
+	if errX != nil {
+		t.Fatalf ("The \"init order\" could not be worked out: %v", errX)
+	}
+	for attempt := 0; attempt < 20; attempt ++ {
+		initOrder, errY, _ := build ().InitOrder ()
+		if errY != nil {
+			t.Fatalf ("The \"init order\" could not be worked out: %v", errY)
+		}
+		if fmt.Sprint (initOrder) != fmt.Sprint (firstOrder) {
+			t.Fatalf ("The \"init order\" is %v, rather than %v.", initOrder,
+				firstOrder)
+		}
+	}
+}
+
+func TestRepeatedDependencies (t *testing.T) {
+
+	someSystem := New ()
+	if errX := someSystem.AddElement ("a", []string {"b", "b", "c", "b"}); errX != nil {
+		t.Fatalf ("Element 'a' could not be added: %v", errX)
+	}
+	dependencies, errY := someSystem.Dependencies ("a")
+	if errY != nil {
+		t.Fatalf ("The dependencies of element 'a' could not be fetched: %v", errY)
+	}
+	if fmt.Sprint (dependencies) != "[b c]" {
+		t.Fatalf ("The dependencies of element 'a' are %v, rather than [b c].",
+			dependencies)
+	}
+}
+
+func TestMissingDependencyAdditionOrder (t *testing.T) {
+
+	cases := []struct {
+		graph map[string][]string
+		missing bool // Tells if a dependency is missing from the graph.
+	} {
+		{map[string][]string {"a": {"b", "c"}, "b": {"c", "d"}, "c": {"d"}, "d": {}},
+			false},
+		{map[string][]string {"a": {"b", "c"}, "b": {"c", "x"}, "c": {}, "d": {"a"}},
+			true},
+	}
+
+	for _, someCase := range cases {
+		for _, elements := range permutations ([]string {"a", "b", "c", "d"}) {
+			someSystem := New ()
+			for _, element := range elements {
+				someSystem.AddElement (element, someCase.graph [element])
+			}
+			_, errX, _ := someSystem.InitOrder ()
+			if (someCase.missing == true && errors.Is (errX, ErrElementMissing) ==
+				false) || (someCase.missing == false && errX != nil) {
+				t.Fatalf ("Elements added in the order %v: unexpected error %v.",
+					elements, errX)
+			}
+		}
+	}
+}
+
+func permutations (items []string) ([][]string) { /* This function returns every order
+	in which some items could be arranged. */
+	if len (items) <= 1 {
+		return [][]string {append ([]string {}, items...)}
+	}
+	all := [][]string {}
+	for index, item := range items {
+		rest := append (append ([]string {}, items [:index]...), items [index + 1:]...)
+		for _, permutation := range permutations (rest) {
+			all = append (all, append ([]string {item}, permutation...))
+		}
+	}
+	return all
+}
+
+func TestMergeOptionalDependencies (t *testing.T) {
+
+	someSystem := New ()
+	someSystem.AddElement ("x", []string {})
+	other := New ()
+	other.AddElementOpt ("x", []string {}, []string {"y"})
+	if errX := someSystem.Merge (other); errors.Is (errX, ErrConflict) == false {
+		t.Fatalf ("The merge returned %v, rather than ErrConflict.", errX)
+	}
+}
+
+func TestClearFrozen (t *testing.T) {
+
+	someSystem := New ()
+	someSystem.AddElement ("a", []string {})
+	someSystem.Freeze ()
+	if errX := someSystem.Clear (); errors.Is (errX, ErrFrozen) == false {
+		t.Fatalf ("Clear () returned %v, rather than ErrFrozen.", errX)
+	}
+	if someSystem.Len () != 1 {
+		t.Fatalf ("The frozen system has %d elements, rather than 1.",
+			someSystem.Len ())
+	}
+}
+
+func TestInitOrderPreferencesLarge (t *testing.T) {
+
+	const count = 60000
+
+	someSystem := New ()
+	for index := 0; index < count; index ++ {
+		someSystem.AddElementPrio (fmt.Sprint (index), []string {}, index % 7)
+	}
+	start := time.Now ()
+	initOrder, errX, _ := someSystem.InitOrder ()
+	if errX != nil {
+		t.Fatalf ("The \"init order\" could not be worked out: %v", errX)
+	}
+	if len (initOrder) != count {
+		t.Fatalf ("The \"init order\" has %d elements, rather than %d.",
+			len (initOrder), count)
+	}
+	if elapsed := time.Since (start); elapsed > 5 * time.Second {
+		t.Fatalf ("The \"init order\" took %s to work out.", elapsed)
+	}
+
+	/* The context is cancelled while the elements are being ordered by preference, so
+		the operation must stop, rather than complete. */
+	ctx, cancel := context.WithCancel (context.Background ())
+	defer cancel ()
+	calls := 0
+	compared := New (WithReadyComparator (func (a, b string) (bool) {
+		calls ++
+		if calls == 1000 {
+			cancel ()
+		}
+		return a < b
+	}))
+	for index := 0; index < count; index ++ {
+		compared.AddElement (fmt.Sprint (index), []string {})
+	}
+	_, errY, _ := compared.InitOrderContext (ctx)
+	if errors.Is (errY, context.Canceled) == false {
+		t.Fatalf ("The operation returned %v, rather than the error of the context.",
+			errY)
+	}
+}
+
+func TestRangeDependenciesReading (t *testing.T) {
+
+	someSystem := New ()
+	someSystem.AddElement ("a", []string {})
+	someSystem.AddElement ("b", []string {"a"})
+	someSystem.AddElement ("c", []string {"b", "a"})
+
+	/* While the function runs, a writer starts waiting for the lock of the system, and
+		the function then reads the system. The read must not wait for the writer. */
+	done := make (chan error)
+	go func () {
+		done <- someSystem.RangeDependencies ("c", func (dep string) (bool) {
+			go someSystem.AddElement ("d:" + dep, []string {})
+			time.Sleep (10 * time.Millisecond)
+			someSystem.Dependencies (dep)
+			return true
+		})
+	} ()
+	select {
+	case errX := <- done:
+		if errX != nil {
+			t.Fatalf ("The dependencies of element 'c' could not be ranged over: %v",
+				errX)
+		}
+	case <- time.After (5 * time.Second):
+		t.Fatal ("The function could not read the system.")
+	}
+}
+
+func TestIsAcyclicDepthLimit (t *testing.T) {
+
+	someSystem := New (WithMaxDepth (1))
+	someSystem.AddElement ("a", []string {})
+	someSystem.AddElement ("b", []string {"a"})
+	someSystem.AddElement ("c", []string {"b"})
+	if someSystem.IsAcyclic () == false {
+		t.Fatal ("A system without circles is reported as cyclic.")
+	}
+	someSystem.AddDependency ("a", "c")
+	if someSystem.IsAcyclic () == true {
+		t.Fatal ("A system with a circle is reported as acyclic.")
+	}
+}
+
+func TestTransitiveDependenciesDepthLimit (t *testing.T) {
+
+	someSystem := New (WithMaxDepth (1))
+	someSystem.AddElement ("a", []string {})
+	someSystem.AddElement ("b", []string {"a"})
+	someSystem.AddElement ("c", []string {"b"})
+	_, errX, _ := someSystem.InitOrderFor ("c")
+	_, errY, _ := someSystem.TransitiveDependencies ("c")
+	if errors.Is (errX, ErrMaxDepthExceeded) == false ||
+		errors.Is (errY, ErrMaxDepthExceeded) == false {
+		t.Fatalf ("InitOrderFor () returned %v, and TransitiveDependencies () returned " +
+			"%v, rather than ErrMaxDepthExceeded.", errX, errY)
+	}
+}
+
+func TestCanonicalizeAdditionOrder (t *testing.T) {
+
+	someSystem := New ()
+	someSystem.AddElement ("z", []string {"m", "b"})
+	someSystem.AddElement ("b", []string {})
+	someSystem.AddElement ("m", []string {"b"})
+	other := New ()
+	other.AddElement ("m", []string {"b"})
+	other.AddElement ("b", []string {})
+	other.AddElement ("z", []string {"b", "m"})
+
+	someSystem.Canonicalize ()
+	other.Canonicalize ()
+	someEncoding, _ := json.Marshal (someSystem)
+	otherEncoding, _ := json.Marshal (other)
+	if string (someEncoding) != string (otherEncoding) {
+		t.Fatalf ("The canonical forms %s and %s differ.", someEncoding, otherEncoding)
+	}
+}
+
+func TestMergeChecksElements (t *testing.T) {
+
+	rejectSlash := func (id string) (error) {
+		if strings.Contains (id, "/") == true {
+			return fmt.Errorf ("ID '%s' contains a slash", id)
+		}
+		return nil
+	}
+	incoming := []*System {New (), New ()}
+	incoming [0].AddElement ("ok", []string {})
+	incoming [0].AddElement ("bad/id", []string {})
+	incoming [1].AddElement ("ok", []string {})
+	incoming [1].AddElement ("web", []string {"nope"})
+
+	for _, other := range incoming {
+		someSystem := New (WithIDValidator (rejectSlash), WithStrictDeps ())
+		if errX := someSystem.Merge (other); errX == nil {
+			t.Fatalf ("Merging %v succeeded.", other)
+		}
+		if someSystem.Len () != 0 {
+			t.Fatalf ("A failed merge copied %v.", someSystem.Elements ())
+		}
+
+		encoding, _ := json.Marshal (other)
+		decoded := New (WithIDValidator (rejectSlash), WithStrictDeps ())
+		if errY := json.Unmarshal (encoding, decoded); errY == nil {
+			t.Fatalf ("Decoding %s succeeded.", encoding)
+		}
+	}
+}
+
+func TestConcurrentUse (t *testing.T) {
+
+	const count = 50
+
+	var someSystem *System
+	someSystem = New (WithOrderHook (func (id string, index, total int) {
+		// The system is unlocked while the hook runs, so it could be used here.
+		someSystem.Elements ()
+		someSystem.HasElement (id)
+	}))
+
+	var waitGroup sync.WaitGroup
+	for index := 0; index < count; index ++ {
+		waitGroup.Add (2)
+		go func (index int) {
+			defer waitGroup.Done ()
+			dependencies := []string {}
+			if index > 0 {
+				dependencies = append (dependencies, fmt.Sprint (index - 1))
+			}
+			errX := someSystem.AddElement (fmt.Sprint (index), dependencies)
+			if errX != nil {
+				t.Errorf ("Element %d could not be added: %v", index, errX)
+			}
+		} (index)
+		go func () {
+			defer waitGroup.Done ()
+			_, errX, _ := someSystem.InitOrder ()
+			if errX != nil && errors.Is (errX, ErrElementMissing) == false &&
+				errors.Is (errX, ErrConcurrentModification) == false {
+				t.Errorf ("Unexpected error: %v", errX)
+			}
+			someSystem.Elements ()
+		} ()
+	}
+	waitGroup.Wait ()
+
+	initOrder, errY, errDescp := someSystem.InitOrder ()
+	if errY != nil {
+		t.Fatalf ("The \"init order\" could not be worked out: %v: %s", errY, errDescp)
+	}
+	if len (initOrder) != count {
+		t.Fatalf ("The \"init order\" has %d elements, rather than %d.",
+			len (initOrder), count)
+	}
+}
+
+func FuzzDependentsIndex (f *testing.F) {
+
+	f.Add ([]byte {0, 0, 1, 0, 1, 2, 2, 0, 3, 1, 3, 4, 0, 2, 5, 1, 6, 2, 1})
+	f.Add ([]byte {0, 1, 2, 0, 2, 0, 6, 2, 4, 1, 2, 5, 0, 3, 1, 4, 3})
+
+	ids := []string {"a", "b", "c", "d", "e", "f"}
+	f.Fuzz (func (t *testing.T, data []byte) {
+		someSystem := New ()
+		for len (data) >= 3 {
+			operation, element := data [0] % 7, ids [int (data [1]) % len (ids)]
+			other := ids [int (data [2]) % len (ids)]
+			another := ids [(int (data [2]) + 1) % len (ids)]
+			data = data [3:]
+
+			switch operation {
+			case 0:
+				someSystem.AddElement (element, []string {other})
+			case 1:
+				someSystem.RemoveElement (element)
+			case 2:
+				someSystem.UpdateDependencies (element, []string {other, another})
+			case 3:
+				someSystem.AddDependency (element, other)
+			case 4:
+				someSystem.RemoveDependency (element, other)
+			case 5:
+				someSystem.Rename (element, other)
+			case 6:
+				someSystem.RemoveElementAndRewire (element)
+			}
+			checkDependentsIndex (t, someSystem)
+		}
+	})
+}
+
+func checkDependentsIndex (t *testing.T, someSystem *System) { /* This function checks
+	that the index of dependents of a system matches the one rebuilt from the
+	dependencies of its elements. */
+
+	t.Helper ()
+	rebuilt := map[string][]string {}
+	for _, element := range someSystem.systemElements {
+		for _, dep := range someSystem.dependencies [element] {
+			rebuilt [dep] = append (rebuilt [dep], element)
+		}
+	}
+	if len (someSystem.dependencies) != len (someSystem.systemElements) {
+		t.Fatalf ("%d elements have dependencies recorded, but the system has %d " +
+			"elements.", len (someSystem.dependencies), len (someSystem.systemElements))
+	}
+	if len (someSystem.dependents) != len (rebuilt) {
+		t.Fatalf ("The index of dependents is %v, rather than %v.",
+			someSystem.dependents, rebuilt)
+	}
+	for dep, dependents := range rebuilt {
+		sort.Strings (dependents)
+		indexed := append ([]string {}, someSystem.dependents [dep]...)
+		sort.Strings (indexed)
+		if fmt.Sprint (indexed) != fmt.Sprint (dependents) {
+			t.Fatalf ("The index of dependents is %v, rather than %v.",
+				someSystem.dependents, rebuilt)
+		}
+	}
+}
+
+func benchmarkSystem (size int) (*System) { /* This function builds a system for
+	benchmarks: a system of some elements, each depending on up to three elements
+	added before it. */
+	someSystem := New ()
+	for index := 0; index < size; index ++ {
+		dependencies := []string {}
+		for offset := 1; offset <= 3 && offset <= index; offset ++ {
+			dependencies = append (dependencies, fmt.Sprint (index - offset * 7 % index))
+		}
+		someSystem.AddElement (fmt.Sprint (index), dependencies)
+	}
+	return someSystem
+}
+
+func benchmarkWrite (b *testing.B, write func (*System, io.Writer) (error)) { /* This
+	function benchmarks an operation writing a system, for systems of growing sizes.
+	Besides the allocations, the greatest growth of the live heap while the system is
+	being written is reported, as metric "peak-live-B/op". It should stay about the
+	same, whatever the size of the system, for an operation writing as it goes. */
+
+	for _, size := range []int {1000, 100000} {
+		someSystem := benchmarkSystem (size)
+		counter := &heapSampler {}
+		if errX := write (someSystem, counter); errX != nil {
+			b.Fatal (errX)
+		}
+
+		b.Run (fmt.Sprint (size), func (b *testing.B) {
+			b.ReportAllocs ()
+			peak := uint64 (0)
+			for index := 0; index < b.N; index ++ {
+				sampler := &heapSampler {b: b, every: counter.written / 16 + 1}
+				sampler.next = sampler.every
+				b.StopTimer ()
+				sampler.base = liveHeap ()
+				b.StartTimer ()
+				if errX := write (someSystem, sampler); errX != nil {
+					b.Fatal (errX)
+				}
+				if sampler.peak > sampler.base && sampler.peak - sampler.base > peak {
+					peak = sampler.peak - sampler.base
+				}
+			}
+			b.ReportMetric (float64 (peak), "peak-live-B/op")
+		})
+	}
+}
+
+type heapSampler struct { /* A writer that discards what is written to it, but samples
+	the live heap about every some bytes written, remembering the greatest sample. */
+	b *testing.B // The benchmark, whose timer is stopped while the heap is sampled.
+	every int // The number of bytes between samples. Value 0 means no sample is taken.
+	written int // The number of bytes written so far.
+	next int // The number of bytes written, at which the next sample is taken.
+	base uint64 // The live heap before the writing started.
+	peak uint64 // The greatest sample.
+}
+
+func (sampler *heapSampler) Write (data []byte) (int, error) {
+	sampler.written += len (data)
+	if sampler.every > 0 && sampler.written >= sampler.next {
+		sampler.next += sampler.every
+		sampler.b.StopTimer ()
+		if live := liveHeap (); live > sampler.peak {
+			sampler.peak = live
+		}
+		sampler.b.StartTimer ()
+	}
+	return len (data), nil
+}
+
+func liveHeap () (uint64) { /* This function returns the size of the objects on the heap
+	still in use, once garbage has been collected. */
+	runtime.GC ()
+	var stats runtime.MemStats
+	runtime.ReadMemStats (&stats)
+	return stats.HeapAlloc
+}
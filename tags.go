@@ -0,0 +1,109 @@
+package system
+
+import (
+	"container/heap"
+	"context"
+)
+
+// Adds an element to the system, just like AddElement (), and tags it. Tags are used by
+// InitOrderGrouped () to keep related elements together.
+//
+// Inputs
+//
+// input 0: The new element to be added to the system.
+//
+// input 1: The IDs of the dependencies of the element.
+//
+// input 2: The tag of the element. Elements added using AddElement () have the empty
+// string as their tag.
+//
+// Outpts
+//
+// outpt 0: Possible errors are the same as those of AddElement ().
+func (someSystem *System) AddElementTagged (newElement string, dependencies []string,
+	tag string) (error) {
+
+	someSystem.mutex.Lock ()
+	defer someSystem.mutex.Unlock ()
+
+	if someSystem.frozen == true {
+		return ErrFrozen
+	}
+
+	if errX := someSystem.addElement (newElement, dependencies); errX != nil {
+		return errX
+	}
+	if tag != "" {
+		someSystem.tags [newElement] = tag
+	}
+	return nil
+}
+
+// This function provides an order in which elements of the system could be safely
+// initialized, just like InitOrder (), except that elements with the same tag are kept
+// next to each other, wherever the dependencies allow it. Whenever an element is to be
+// chosen, an element with the same tag as the element before it is preferred; when
+// there is none, the ready element added to the system earliest is chosen.
+//
+// Outpts
+//
+// outpt 0, outpt 1, and outpt 2: The same as those of InitOrder ().
+func (someSystem *System) InitOrderGrouped () ([]string, error, string) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	if errX, errDescp := someSystem.validate (true); errX != nil {
+		return nil, errX, errDescp
+	}
+
+	/* Ready elements are kept in a heap of their own, ordered by the position of the
+		elements in the system, and also in a heap for their tag, so the ready
+		element with a tag could be found quickly. An element taken from one heap is
+		left in the other, and skipped once it reaches its top. */
+	position := make (map[string]int, len (someSystem.systemElements))
+	for index, element := range someSystem.systemElements {
+		position [element] = index
+	}
+	earlier := func (a, b string) (bool) {
+		return position [a] < position [b]
+	}
+	ready := &elementHeap {less: earlier}
+	readyByTag := map[string]*elementHeap {}
+	taken := map[string]bool {}
+	next := func (someHeap *elementHeap) (string) {
+		for someHeap.Len () > 0 {
+			element := heap.Pop (someHeap).(string)
+			if taken [element] == false {
+				return element
+			}
+		}
+		return ""
+	}
+
+	lastTag := ""
+	initOrder, errX, errDescp := someSystem.orderByReadiness (context.Background (),
+		someSystem.systemElements, func (element string) {
+		heap.Push (ready, element)
+		tag := someSystem.tags [element]
+		if readyByTag [tag] == nil {
+			readyByTag [tag] = &elementHeap {less: earlier}
+		}
+		heap.Push (readyByTag [tag], element)
+	}, func () (string) {
+		element := ""
+		if readyByTag [lastTag] != nil {
+			element = next (readyByTag [lastTag])
+		}
+		if element == "" {
+			element = next (ready)
+		}
+		taken [element] = true
+		lastTag = someSystem.tags [element]
+		return element
+	})
+	if errX != nil {
+		return nil, errX, errDescp
+	}
+	return initOrder, nil, ""
+}
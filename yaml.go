@@ -0,0 +1,99 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+)
+
+// Creates a new system from YAML. The YAML must be a mapping of the ID of each element to
+// the list of its dependencies, like the following:
+//
+// 	db: []
+// 	cache: []
+// 	web: [db, cache]
+//
+// An element whose list is empty or left out has no dependency. Elements are added in the
+// order they appear, each just like with AddElement ().
+//
+// Inputs
+//
+// input 0: Where the YAML should be read from.
+//
+// Outpts
+//
+// outpt 0: The new system. If an error occurs, value would be nil.
+//
+// outpt 1: Possible errors include: the errors of reading and decoding the YAML, and the
+// errors of AddElement ().
+func FromYAML (r io.Reader) (*System, error) {
+
+	/* The YAML is decoded as a node, rather than as a hash map, so the order in which
+		elements appear is not lost. */
+	var document yaml.Node
+	errX := yaml.NewDecoder (r).Decode (&document)
+	if errX == io.EOF {
+		return New (), nil
+	}
+	if errX != nil {
+		return nil, errX
+	}
+
+	mapping := &document
+	if mapping.Kind == yaml.DocumentNode && len (mapping.Content) > 0 {
+		mapping = mapping.Content [0]
+	}
+	if mapping.Kind != yaml.MappingNode {
+		return nil, errors.New ("The YAML is not a mapping of elements to their " +
+			"dependencies.")
+	}
+
+	newSystem := New ()
+	for index := 0; index + 1 < len (mapping.Content); index += 2 {
+		element := mapping.Content [index].Value
+		var deps []string
+		if errY := mapping.Content [index + 1].Decode (&deps); errY != nil {
+			return nil, fmt.Errorf ("element '%s': %w", element, errY)
+		}
+		if errZ := newSystem.AddElement (element, deps); errZ != nil {
+			return nil, errZ
+		}
+	}
+	return newSystem, nil
+}
+
+// Writes the system as YAML, in the form read by FromYAML (). Elements appear in the
+// order they were added to the system, so the "init order" survives a round trip,
+// provided no element has optional dependencies or a priority: the form has no place
+// for them, hence they are not written.
+//
+// Inputs
+//
+// input 0: Where the YAML should be written.
+//
+// Outpts
+//
+// outpt 0: Possible errors include: the errors of encoding and writing the YAML.
+func (someSystem *System) ToYAML (w io.Writer) (error) {
+
+	someSystem.mutex.RLock ()
+	defer someSystem.mutex.RUnlock ()
+
+	mapping := &yaml.Node {Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, element := range someSystem.systemElements {
+		deps := &yaml.Node {Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, dep := range someSystem.dependencies [element] {
+			deps.Content = append (deps.Content, &yaml.Node {Kind: yaml.ScalarNode,
+				Tag: "!!str", Value: dep})
+		}
+		mapping.Content = append (mapping.Content, &yaml.Node {Kind: yaml.ScalarNode,
+			Tag: "!!str", Value: element}, deps)
+	}
+
+	encoder := yaml.NewEncoder (w)
+	if errX := encoder.Encode (mapping); errX != nil {
+		return errX
+	}
+	return encoder.Close ()
+}